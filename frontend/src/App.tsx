@@ -42,10 +42,11 @@ function App() {
           fixedValues[key] = value;
         }
       });
-      const result: TruthTableEntry[] = await CalculateTruthTable(logicalExpression, fixedValues);
+      const result: TruthTableEntry[] = await CalculateTruthTable(logicalExpression, fixedValues, variables);
       setTruthTableData(result);
     } catch (err: any) {
-      setError(err.message || 'Ошибка при вычислении выражения');
+      // Ошибки разбора приходят как объект с полями Message и Diagnostics
+      setError(err?.Message || (typeof err === 'string' ? err : err?.message) || 'Ошибка при вычислении выражения');
     } finally {
       setIsLoading(false);
     }
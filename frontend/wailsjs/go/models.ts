@@ -1,5 +1,724 @@
+export namespace ast {
+	
+	export class VariableClash {
+	    Name: string;
+	    Free: lexer.Span;
+	    Binder: lexer.Span;
+	
+	    static createFrom(source: any = {}) {
+	        return new VariableClash(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Free = this.convertValues(source["Free"], lexer.Span);
+	        this.Binder = this.convertValues(source["Binder"], lexer.Span);
+	    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class VariableAnalysis {
+	    Free: string[];
+	    Bound: string[];
+	    Clashes: VariableClash[];
+	
+	    static createFrom(source: any = {}) {
+	        return new VariableAnalysis(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Free = source["Free"];
+	        this.Bound = source["Bound"];
+	        this.Clashes = this.convertValues(source["Clashes"], VariableClash);
+	    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace base {
+	
+	export class RuleApplication {
+	    Name: string;
+	    Description: string;
+	    Before: string;
+	    After: string;
+	    Span: lexer.Span;
+	
+	    static createFrom(source: any = {}) {
+	        return new RuleApplication(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Description = source["Description"];
+	        this.Before = source["Before"];
+	        this.After = source["After"];
+	        this.Span = this.convertValues(source["Span"], lexer.Span);
+	    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace fol {
+	
+	export class ProofClause {
+	    ID: number;
+	    Clause: string;
+	    Rule: string;
+	    Parents: number[];
+	    Renaming: Record<string, string>;
+	    Unifier: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProofClause(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ID = source["ID"];
+	        this.Clause = source["Clause"];
+	        this.Rule = source["Rule"];
+	        this.Parents = source["Parents"];
+	        this.Renaming = source["Renaming"];
+	        this.Unifier = source["Unifier"];
+	    }
+	}
+	export class Proof {
+	    Formula: string;
+	    Negation: string;
+	    Steps: base.RuleApplication[];
+	    Clauses: string[];
+	    Proved: boolean;
+	    Refutation: ProofClause[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Proof(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Formula = source["Formula"];
+	        this.Negation = source["Negation"];
+	        this.Steps = this.convertValues(source["Steps"], base.RuleApplication);
+	        this.Clauses = source["Clauses"];
+	        this.Proved = source["Proved"];
+	        this.Refutation = this.convertValues(source["Refutation"], ProofClause);
+	    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RelationRow {
+	    Args: string[];
+	    Holds: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RelationRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Args = source["Args"];
+	        this.Holds = source["Holds"];
+	    }
+	}
+	export class RelationTable {
+	    Name: string;
+	    Arity: number;
+	    Rows: RelationRow[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RelationTable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Name = source["Name"];
+	        this.Arity = source["Arity"];
+	        this.Rows = this.convertValues(source["Rows"], RelationRow);
+	    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CounterModel {
+	    Structure: visitor.Structure;
+	    Propositions: Record<string, boolean>;
+	    Relations: RelationTable[];
+	    Witnesses: visitor.QuantifierWitness[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CounterModel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Structure = this.convertValues(source["Structure"], visitor.Structure);
+	        this.Propositions = source["Propositions"];
+	        this.Relations = this.convertValues(source["Relations"], RelationTable);
+	        this.Witnesses = this.convertValues(source["Witnesses"], visitor.QuantifierWitness);
+	    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchResult {
+	    Formula: string;
+	    MaxDomain: number;
+	    CounterModel?: CounterModel;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Formula = source["Formula"];
+	        this.MaxDomain = source["MaxDomain"];
+	        this.CounterModel = this.convertValues(source["CounterModel"], CounterModel);
+	    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace lexer {
+	
+	export class Span {
+	    Start: number;
+	    End: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Span(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Start = source["Start"];
+	        this.End = source["End"];
+	    }
+	}
+
+}
+
+export namespace lib {
+	
+	export class TruthTableChunk {
+	    Stream: number;
+	    Page?: visitor.TruthTablePage;
+	    Last: boolean;
+	    Error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TruthTableChunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Stream = source["Stream"];
+	        this.Page = this.convertValues(source["Page"], visitor.TruthTablePage);
+	        this.Last = source["Last"];
+	        this.Error = source["Error"];
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace qbf {
+	
+	export class StrategyRow {
+	    Values: boolean[];
+	    Choice: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StrategyRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Values = source["Values"];
+	        this.Choice = source["Choice"];
+	    }
+	}
+	export class Strategy {
+	    Quantifier: string;
+	    Variable: string;
+	    Depends: string[];
+	    Rows: StrategyRow[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Strategy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Quantifier = source["Quantifier"];
+	        this.Variable = source["Variable"];
+	        this.Depends = source["Depends"];
+	        this.Rows = this.convertValues(source["Rows"], StrategyRow);
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Result {
+	    Formula: string;
+	    Prenex: string;
+	    Value: boolean;
+	    Winner: string;
+	    Strategies: Strategy[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Formula = source["Formula"];
+	        this.Prenex = source["Prenex"];
+	        this.Value = source["Value"];
+	        this.Winner = source["Winner"];
+	        this.Strategies = this.convertValues(source["Strategies"], Strategy);
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace sets {
+	
+	export class Region {
+	    Index: number;
+	    Inside: string[];
+	    Outside: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Region(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Index = source["Index"];
+	        this.Inside = source["Inside"];
+	        this.Outside = source["Outside"];
+	    }
+	}
+	export class Counterexample {
+	    Region: Region;
+	    InLeft: boolean;
+	    InRight: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Counterexample(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Region = this.convertValues(source["Region"], Region);
+	        this.InLeft = source["InLeft"];
+	        this.InRight = source["InRight"];
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Diagram {
+	    Expression: string;
+	    Sets: string[];
+	    Regions: Region[];
+	    Covered: boolean[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Diagram(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Expression = source["Expression"];
+	        this.Sets = source["Sets"];
+	        this.Regions = this.convertValues(source["Regions"], Region);
+	        this.Covered = source["Covered"];
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IdentityResult {
+	    Left: string;
+	    Right: string;
+	    Formula: string;
+	    Holds: boolean;
+	    Counterexample?: Counterexample;
+	
+	    static createFrom(source: any = {}) {
+	        return new IdentityResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Left = source["Left"];
+	        this.Right = source["Right"];
+	        this.Formula = source["Formula"];
+	        this.Holds = source["Holds"];
+	        this.Counterexample = this.convertValues(source["Counterexample"], Counterexample);
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace visitor {
 	
+	export class Derivation {
+	    Result: string;
+	    Steps: base.RuleApplication[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Derivation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Result = source["Result"];
+	        this.Steps = this.convertValues(source["Steps"], base.RuleApplication);
+	    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FunctionMapping {
+	    Args: string[];
+	    Value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FunctionMapping(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Args = source["Args"];
+	        this.Value = source["Value"];
+	    }
+	}
+	export class QuantifierWitness {
+	    Formula: string;
+	    Variable: string;
+	    Result: boolean;
+	    Element: string;
+	    Bindings: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuantifierWitness(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Formula = source["Formula"];
+	        this.Variable = source["Variable"];
+	        this.Result = source["Result"];
+	        this.Element = source["Element"];
+	        this.Bindings = source["Bindings"];
+	    }
+	}
+	export class SetResult {
+	    Statement: string;
+	    Name: string;
+	    Elements: string[];
+	    Member?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SetResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Statement = source["Statement"];
+	        this.Name = source["Name"];
+	        this.Elements = source["Elements"];
+	        this.Member = source["Member"];
+	    }
+	}
+	export class Structure {
+	    Domain: string[];
+	    Predicates: Record<string, Array<Array<string>>>;
+	    Functions: Record<string, Array<FunctionMapping>>;
+	    Constants: Record<string, string>;
+	    Sets: Record<string, Array<string>>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Structure(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Domain = source["Domain"];
+	        this.Predicates = source["Predicates"];
+	        this.Functions = this.convertValues(source["Functions"], Array<FunctionMapping>, true);
+	        this.Constants = source["Constants"];
+	        this.Sets = source["Sets"];
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SubformulaValue {
+	    Formula: string;
+	    Value: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubformulaValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Formula = source["Formula"];
+	        this.Value = source["Value"];
+	    }
+	}
 	export class TruthTableVariable {
 	    Name: string;
 	    Value: boolean;
@@ -15,8 +734,11 @@ export namespace visitor {
 	    }
 	}
 	export class TruthTableEntry {
+	    Minterm: number;
 	    Result: boolean;
 	    Variables: TruthTableVariable[];
+	    Witnesses: QuantifierWitness[];
+	    Subformulas: SubformulaValue[];
 	
 	    static createFrom(source: any = {}) {
 	        return new TruthTableEntry(source);
@@ -24,8 +746,11 @@ export namespace visitor {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Minterm = source["Minterm"];
 	        this.Result = source["Result"];
 	        this.Variables = this.convertValues(source["Variables"], TruthTableVariable);
+	        this.Witnesses = this.convertValues(source["Witnesses"], QuantifierWitness);
+	        this.Subformulas = this.convertValues(source["Subformulas"], SubformulaValue);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -46,6 +771,46 @@ export namespace visitor {
 		    return a;
 		}
 	}
+	export class TruthTablePage {
+	    Variables: string[];
+	    TotalRows: number;
+	    Rows: TruthTableEntry[];
+	    More: boolean;
+	    Next: number;
+	    Skip: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TruthTablePage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Variables = source["Variables"];
+	        this.TotalRows = source["TotalRows"];
+	        this.Rows = this.convertValues(source["Rows"], TruthTableEntry);
+	        this.More = source["More"];
+	        this.Next = source["Next"];
+	        this.Skip = source["Skip"];
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
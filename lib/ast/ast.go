@@ -25,9 +25,21 @@ var (
 
 // ASTNode represents a node in the abstract syntax tree for logical expressions.
 type ASTNode interface {
+	// Equals reports whether the node is structurally equal to other, as
+	// defined by Equal.
 	Equals(other ASTNode) bool
 	String() string
+	// Hash returns the 64-bit FNV-1a hash of a tag naming the node type, its
+	// operator or name, and the hashes of its children as 8 little-endian
+	// bytes each. The child hashes of commutative operators are sorted first
+	// and bound variables are renamed canonically. The hash depends on the
+	// structure alone, so it is the same in every run and on every platform
+	// and may serve as a cache key. Equal nodes hash alike; nodes that hash
+	// alike need not be equal.
 	Hash() uint64
+	// Span returns the part of the source the node was parsed from.
+	Span() lexer.Span
+	SetSpan(span lexer.Span)
 }
 
 // Traversable represents nodes that can be traversed (have children).
@@ -49,8 +61,39 @@ type Mutable interface {
 	Remove(node ASTNode) bool
 }
 
+// Spanned stores the source span of a node. Every node embeds it; the span
+// takes no part in Equals or Hash.
+type Spanned struct {
+	span lexer.Span
+}
+
+func (s *Spanned) Span() lexer.Span {
+	return s.span
+}
+
+func (s *Spanned) SetSpan(span lexer.Span) {
+	s.span = span
+}
+
+// InheritSpan gives node, and every node below it that has no span yet, the
+// given span. It is used for nodes built by rewrites, which have no source of
+// their own; subtrees that already carry a span keep it.
+func InheritSpan(node ASTNode, span lexer.Span) {
+	if node == nil || !node.Span().IsZero() {
+		return
+	}
+	node.SetSpan(span)
+
+	if traversable, ok := node.(Traversable); ok {
+		for _, child := range traversable.Children() {
+			InheritSpan(child, span)
+		}
+	}
+}
+
 // GroupingNode represents parenthesized expressions.
 type GroupingNode struct {
+	Spanned
 	Expr ASTNode
 }
 
@@ -60,7 +103,7 @@ func NewGroupingNode(expr ASTNode) *GroupingNode {
 
 func (g *GroupingNode) Equals(other ASTNode) bool {
 	node, ok := other.(*GroupingNode)
-	return ok && g.Hash() == node.Hash()
+	return ok && Equal(g, node)
 }
 
 func (g *GroupingNode) Children() []ASTNode {
@@ -84,6 +127,7 @@ func (g *GroupingNode) Hash() uint64 {
 
 // LiteralNode represents boolean literals (true/false).
 type LiteralNode struct {
+	Spanned
 	Value bool
 }
 
@@ -93,7 +137,7 @@ func NewLiteralNode(value bool) *LiteralNode {
 
 func (l *LiteralNode) Equals(other ASTNode) bool {
 	node, ok := other.(*LiteralNode)
-	return ok && l.Hash() == node.Hash()
+	return ok && Equal(l, node)
 }
 
 func (l *LiteralNode) Children() []ASTNode {
@@ -124,6 +168,7 @@ func (l *LiteralNode) Hash() uint64 {
 
 // VariableNode represents logical variables.
 type VariableNode struct {
+	Spanned
 	Name string
 }
 
@@ -133,7 +178,7 @@ func NewVariableNode(name string) *VariableNode {
 
 func (v *VariableNode) Equals(other ASTNode) bool {
 	node, ok := other.(*VariableNode)
-	return ok && v.Hash() == node.Hash()
+	return ok && Equal(v, node)
 }
 
 func (v *VariableNode) Children() []ASTNode {
@@ -157,6 +202,7 @@ func (v *VariableNode) Hash() uint64 {
 
 // BinaryNode represents binary logical operations.
 type BinaryNode struct {
+	Spanned
 	Operator    lexer.BooleanTokenType
 	Left, Right ASTNode
 }
@@ -171,7 +217,7 @@ func NewBinaryNode(operator lexer.BooleanTokenType, left, right ASTNode) *Binary
 
 func (b *BinaryNode) Equals(other ASTNode) bool {
 	node, ok := other.(*BinaryNode)
-	return ok && b.Hash() == node.Hash()
+	return ok && Equal(b, node)
 }
 
 func (b *BinaryNode) Children() []ASTNode {
@@ -194,13 +240,13 @@ func (b *BinaryNode) Hash() uint64 {
 	h.Write([]byte("binary"))
 	h.Write([]byte(b.Operator.String()))
 
-	// For implication (IMPL), order matters
-	if b.Operator == lexer.IMPL {
+	// For implications (IMPL, CONV), order matters
+	if !b.Operator.IsCommutative() {
 		h.Write(utils.Uint64ToBytes(b.Left.Hash()))
 		h.Write(utils.Uint64ToBytes(b.Right.Hash()))
 		return h.Sum64()
 	}
-	// For associative operations (CONJ, DISJ), use order-independent hash
+	// For commutative operations (CONJ, DISJ, EQUIV, XOR, NAND, NOR), use order-independent hash
 	leftHash := b.Left.Hash()
 	rightHash := b.Right.Hash()
 
@@ -219,6 +265,7 @@ func (b *BinaryNode) Hash() uint64 {
 // ChainNode represents a flattened chain of binary operations of the same type.
 // This optimization reduces tree depth for associative operations.
 type ChainNode struct {
+	Spanned
 	Operator lexer.BooleanTokenType // CONJ or DISJ
 	Operands []ASTNode              // Must have at least 2 operands
 }
@@ -236,7 +283,7 @@ func NewChainNode(operator lexer.BooleanTokenType, operands ...ASTNode) (*ChainN
 
 func (c *ChainNode) Equals(other ASTNode) bool {
 	node, ok := other.(*ChainNode)
-	return ok && c.Hash() == node.Hash()
+	return ok && Equal(c, node)
 }
 
 func (c *ChainNode) Children() []ASTNode {
@@ -321,6 +368,7 @@ func (c *ChainNode) ToBinary() ASTNode {
 
 // UnaryNode represents unary logical operations (primarily negation).
 type UnaryNode struct {
+	Spanned
 	Operator lexer.BooleanTokenType
 	Operand  ASTNode
 }
@@ -334,7 +382,7 @@ func NewUnaryNode(operator lexer.BooleanTokenType, operand ASTNode) *UnaryNode {
 
 func (u *UnaryNode) Equals(other ASTNode) bool {
 	node, ok := other.(*UnaryNode)
-	return ok && u.Hash() == node.Hash()
+	return ok && Equal(u, node)
 }
 
 func (u *UnaryNode) Children() []ASTNode {
@@ -357,8 +405,11 @@ func (u *UnaryNode) Hash() uint64 {
 	return h.Sum64()
 }
 
-// PredicateNode represents predicate logic expressions (future extension).
+// PredicateNode represents an atomic formula P(t1, ..., tn). Args are terms:
+// VariableNode and FunctionNode. A predicate without arguments is a
+// propositional letter.
 type PredicateNode struct {
+	Spanned
 	Name string
 	Args []ASTNode // Changed from 'any' to []ASTNode for type safety
 }
@@ -372,7 +423,7 @@ func NewPredicateNode(name string, args ...ASTNode) *PredicateNode {
 
 func (p *PredicateNode) Equals(other ASTNode) bool {
 	node, ok := other.(*PredicateNode)
-	return ok && p.Hash() == node.Hash()
+	return ok && Equal(p, node)
 }
 
 func (p *PredicateNode) Children() []ASTNode {
@@ -399,11 +450,70 @@ func (p *PredicateNode) String() string {
 }
 
 func (p *PredicateNode) Hash() uint64 {
-	panic("implement me")
+	h := fnv.New64a()
+	h.Write([]byte("predicate"))
+	h.Write([]byte(p.Name))
+	for _, arg := range p.Args {
+		h.Write(utils.Uint64ToBytes(arg.Hash()))
+	}
+	return h.Sum64()
+}
+
+// FunctionNode represents a term f(t1, ..., tn) built from a function symbol.
+type FunctionNode struct {
+	Spanned
+	Name string
+	Args []ASTNode
+}
+
+func NewFunctionNode(name string, args ...ASTNode) *FunctionNode {
+	return &FunctionNode{
+		Name: name,
+		Args: slices.Clone(args),
+	}
+}
+
+func (f *FunctionNode) Equals(other ASTNode) bool {
+	node, ok := other.(*FunctionNode)
+	return ok && Equal(f, node)
+}
+
+func (f *FunctionNode) Children() []ASTNode {
+	return slices.Clone(f.Args)
+}
+
+func (f *FunctionNode) Contains(node ASTNode) bool {
+	return slices.ContainsFunc(f.Args, func(arg ASTNode) bool {
+		return arg.Equals(node)
+	})
+}
+
+func (f *FunctionNode) String() string {
+	argStrs := make([]string, len(f.Args))
+	for i, arg := range f.Args {
+		argStrs[i] = arg.String()
+	}
+
+	return fmt.Sprintf("%s(%s)", f.Name, strings.Join(argStrs, ", "))
 }
 
-// QuantifierNode represents quantified expressions (∀, ∃).
+func (f *FunctionNode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("function"))
+	h.Write([]byte(f.Name))
+	for _, arg := range f.Args {
+		h.Write(utils.Uint64ToBytes(arg.Hash()))
+	}
+	return h.Sum64()
+}
+
+// QuantifierNode represents quantified expressions (∀, ∃). Domain is nil
+// when the variable ranges over the whole universe of discourse, and a
+// SetNameNode when it ranges over a named set only. A quantifier whose
+// variable is never an argument of a predicate or function quantifies over
+// truth values instead, as in the quantified boolean formula ∀p ∃q (p ↔ q).
 type QuantifierNode struct {
+	Spanned
 	Type     lexer.BooleanTokenType
 	Variable string
 	Domain   ASTNode // Changed from 'any' to ASTNode
@@ -419,29 +529,145 @@ func NewQuantifierNode(qType lexer.BooleanTokenType, variable string, domain, bo
 	}
 }
 
+// Propositional reports whether q quantifies over truth values: it is not
+// bounded and its variable does not occur free as a term in the body.
+func (q *QuantifierNode) Propositional() bool {
+	if q.Domain != nil {
+		return false
+	}
+
+	var asTerm func(node ASTNode) bool
+	asTerm = func(node ASTNode) bool {
+		switch n := node.(type) {
+		case *VariableNode:
+			return n.Name == q.Variable
+		case *FunctionNode:
+			return slices.ContainsFunc(n.Args, asTerm)
+		}
+		return false
+	}
+
+	var walk func(node ASTNode) bool
+	walk = func(node ASTNode) bool {
+		switch n := node.(type) {
+		case *PredicateNode:
+			return slices.ContainsFunc(n.Args, asTerm)
+		case *QuantifierNode:
+			return n.Variable != q.Variable && walk(n.Body)
+		case Traversable:
+			return slices.ContainsFunc(n.Children(), walk)
+		}
+		return false
+	}
+	return !walk(q.Body)
+}
+
 func (q *QuantifierNode) Equals(other ASTNode) bool {
 	node, ok := other.(*QuantifierNode)
-	return ok && q.Hash() == node.Hash()
+	return ok && Equal(q, node)
 }
 
 func (q *QuantifierNode) Children() []ASTNode {
+	if q.Domain == nil {
+		return []ASTNode{q.Body}
+	}
 	return []ASTNode{q.Domain, q.Body}
 }
 
 func (q *QuantifierNode) Contains(node ASTNode) bool {
-	return q.Domain.Equals(node) || q.Body.Equals(node)
+	return (q.Domain != nil && q.Domain.Equals(node)) || q.Body.Equals(node)
 }
 
 func (q *QuantifierNode) String() string {
-	return fmt.Sprintf("%s %s: %s . %s",
+	if q.Domain != nil {
+		return fmt.Sprintf("%s%s ∈ %s %s",
+			q.Type.String(),
+			q.Variable,
+			q.Domain.String(),
+			q.Body.String())
+	}
+	return fmt.Sprintf("%s%s %s",
 		q.Type.String(),
 		q.Variable,
-		q.Domain.String(),
 		q.Body.String())
 }
 
+// Hash is invariant under renaming of the bound variable, so that ∀x P(x)
+// and ∀y P(y) hash, and therefore compare, equal. The variable is replaced in
+// the body by a name derived from the number of binders nested below it,
+// which no inner binder shares and no parsed variable can have.
 func (q *QuantifierNode) Hash() uint64 {
-	panic("implement me")
+	h := fnv.New64a()
+	h.Write([]byte("quantifier"))
+	h.Write([]byte(q.Type.String()))
+	if q.Domain != nil {
+		h.Write(utils.Uint64ToBytes(q.Domain.Hash()))
+	}
+
+	canonical := fmt.Sprintf("#%d", binderHeight(q.Body))
+	h.Write(utils.Uint64ToBytes(renameFree(q.Body, q.Variable, canonical).Hash()))
+	return h.Sum64()
+}
+
+// binderHeight returns the largest number of quantifiers nested in node.
+func binderHeight(node ASTNode) int {
+	height := 0
+	if traversable, ok := node.(Traversable); ok {
+		for _, child := range traversable.Children() {
+			height = max(height, binderHeight(child))
+		}
+	}
+	if _, ok := node.(*QuantifierNode); ok {
+		height++
+	}
+	return height
+}
+
+// renameFree returns a copy of node with every free occurrence of the
+// variable from renamed to.
+func renameFree(node ASTNode, from, to string) ASTNode {
+	rename := func(node ASTNode) ASTNode {
+		return renameFree(node, from, to)
+	}
+	renameAll := func(nodes []ASTNode) []ASTNode {
+		renamed := make([]ASTNode, len(nodes))
+		for i, n := range nodes {
+			renamed[i] = rename(n)
+		}
+		return renamed
+	}
+
+	switch n := node.(type) {
+	case *VariableNode:
+		if n.Name == from {
+			return &VariableNode{Spanned: n.Spanned, Name: to}
+		}
+		return n
+	case *GroupingNode:
+		return &GroupingNode{Spanned: n.Spanned, Expr: rename(n.Expr)}
+	case *BinaryNode:
+		return &BinaryNode{Spanned: n.Spanned, Operator: n.Operator, Left: rename(n.Left), Right: rename(n.Right)}
+	case *ChainNode:
+		return &ChainNode{Spanned: n.Spanned, Operator: n.Operator, Operands: renameAll(n.Operands)}
+	case *UnaryNode:
+		return &UnaryNode{Spanned: n.Spanned, Operator: n.Operator, Operand: rename(n.Operand)}
+	case *PredicateNode:
+		return &PredicateNode{Spanned: n.Spanned, Name: n.Name, Args: renameAll(n.Args)}
+	case *FunctionNode:
+		return &FunctionNode{Spanned: n.Spanned, Name: n.Name, Args: renameAll(n.Args)}
+	case *QuantifierNode:
+		renamed := &QuantifierNode{Spanned: n.Spanned, Type: n.Type, Variable: n.Variable, Domain: n.Domain, Body: n.Body}
+		if n.Domain != nil {
+			renamed.Domain = rename(n.Domain)
+		}
+		// An inner binder of the same variable shadows it.
+		if n.Variable != from {
+			renamed.Body = rename(n.Body)
+		}
+		return renamed
+	default:
+		return node
+	}
 }
 
 // Utility functions for common AST operations
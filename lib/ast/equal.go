@@ -0,0 +1,153 @@
+package ast
+
+import (
+	"fmt"
+	"logicka/lib/lexer"
+	"slices"
+)
+
+// Equal reports whether a and b are the same formula up to the order of the
+// operands of commutative operators, the order and repetition of the
+// elements of set literals and the names of bound variables. Equal nodes
+// always have equal hashes, so differing hashes reject a pair at once; equal
+// hashes are confirmed by comparing the trees, as different nodes may share
+// a hash.
+func Equal(a, b ASTNode) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Hash() == b.Hash() && equal(a, b)
+}
+
+// equal compares a and b node by node. It never looks at hashes.
+func equal(a, b ASTNode) bool {
+	switch x := a.(type) {
+	case *GroupingNode:
+		y, ok := b.(*GroupingNode)
+		return ok && equal(x.Expr, y.Expr)
+	case *LiteralNode:
+		y, ok := b.(*LiteralNode)
+		return ok && x.Value == y.Value
+	case *VariableNode:
+		y, ok := b.(*VariableNode)
+		return ok && x.Name == y.Name
+	case *BinaryNode:
+		y, ok := b.(*BinaryNode)
+		if !ok || x.Operator != y.Operator {
+			return false
+		}
+		if equal(x.Left, y.Left) && equal(x.Right, y.Right) {
+			return true
+		}
+		return x.Operator.IsCommutative() && equal(x.Left, y.Right) && equal(x.Right, y.Left)
+	case *ChainNode:
+		y, ok := b.(*ChainNode)
+		if !ok || x.Operator != y.Operator {
+			return false
+		}
+		if x.Operator == lexer.CONJ || x.Operator == lexer.DISJ {
+			return equalUnordered(x.Operands, y.Operands)
+		}
+		return equalOrdered(x.Operands, y.Operands)
+	case *UnaryNode:
+		y, ok := b.(*UnaryNode)
+		return ok && x.Operator == y.Operator && equal(x.Operand, y.Operand)
+	case *PredicateNode:
+		y, ok := b.(*PredicateNode)
+		return ok && x.Name == y.Name && equalOrdered(x.Args, y.Args)
+	case *FunctionNode:
+		y, ok := b.(*FunctionNode)
+		return ok && x.Name == y.Name && equalOrdered(x.Args, y.Args)
+	case *QuantifierNode:
+		y, ok := b.(*QuantifierNode)
+		if !ok || x.Type != y.Type || (x.Domain == nil) != (y.Domain == nil) {
+			return false
+		}
+		if x.Domain != nil && !equal(x.Domain, y.Domain) {
+			return false
+		}
+		// The bound variables are given the name Hash gives them.
+		height := binderHeight(x.Body)
+		if height != binderHeight(y.Body) {
+			return false
+		}
+		canonical := fmt.Sprintf("#%d", height)
+		return equal(renameFree(x.Body, x.Variable, canonical), renameFree(y.Body, y.Variable, canonical))
+	case *SetNameNode:
+		y, ok := b.(*SetNameNode)
+		return ok && x.Name == y.Name
+	case *SetLiteralNode:
+		y, ok := b.(*SetLiteralNode)
+		if !ok {
+			return false
+		}
+		for _, element := range x.Elements {
+			if !slices.Contains(y.Elements, element) {
+				return false
+			}
+		}
+		for _, element := range y.Elements {
+			if !slices.Contains(x.Elements, element) {
+				return false
+			}
+		}
+		return true
+	case *SetBinaryNode:
+		y, ok := b.(*SetBinaryNode)
+		if !ok || x.Operator != y.Operator {
+			return false
+		}
+		if equal(x.Left, y.Left) && equal(x.Right, y.Right) {
+			return true
+		}
+		return x.Operator != lexer.SUBSTRACT && equal(x.Left, y.Right) && equal(x.Right, y.Left)
+	case *SetGroupingNode:
+		y, ok := b.(*SetGroupingNode)
+		return ok && equal(x.Expr, y.Expr)
+	case *SetComplementNode:
+		y, ok := b.(*SetComplementNode)
+		return ok && equal(x.Operand, y.Operand)
+	case *SetDefinitionNode:
+		y, ok := b.(*SetDefinitionNode)
+		return ok && x.Name == y.Name && equal(x.Value, y.Value)
+	case *SetMembershipNode:
+		y, ok := b.(*SetMembershipNode)
+		return ok && x.Element == y.Element && equal(x.Set, y.Set)
+	}
+	return false
+}
+
+func equalOrdered(a, b []ASTNode) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalUnordered reports whether b is a permutation of a up to equality.
+// As equality is an equivalence, pairing each node of a with the first
+// unused equal node of b finds a matching whenever there is one.
+func equalUnordered(a, b []ASTNode) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+	for _, x := range a {
+		found := false
+		for j, y := range b {
+			if !used[j] && equal(x, y) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
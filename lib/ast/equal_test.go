@@ -0,0 +1,124 @@
+package ast_test
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/parser"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"a ∧ b", "b ∧ a", true},
+		{"a ∨ b", "b ∨ a", true},
+		{"a ↔ b", "b ↔ a", true},
+		{"a → b", "b → a", false},
+		{"(a ∧ b) ∧ c", "c ∧ (b ∧ a)", true},
+		{"a ∧ b ∧ c", "c ∧ a ∧ b", false},
+		{"(a ∨ b) ∧ c", "c ∧ (b ∨ a)", true},
+		{"(a)", "a", false},
+		{"∀x P(x)", "∀y P(y)", true},
+		{"∀x ∃y R(x, y)", "∀y ∃x R(y, x)", true},
+		{"∀x ∃y R(x, y)", "∀x ∃y R(y, x)", false},
+		{"∀x P(x, y)", "∀y P(y, y)", false},
+		{"∀x P(x)", "∃x P(x)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" = "+tt.b, func(t *testing.T) {
+			a, err := parser.Parse(tt.a)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.a, err)
+			}
+			b, err := parser.Parse(tt.b)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.b, err)
+			}
+			if got := ast.Equal(a, b); got != tt.want {
+				t.Errorf("Equal(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if tt.want && a.Hash() != b.Hash() {
+				t.Errorf("equal formulas %s and %s hash differently", tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestEqualSets(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"A ∪ B", "B ∪ A", true},
+		{"A ∩ B", "B ∩ A", true},
+		{"A \\ B", "B \\ A", false},
+		{"A \\ B", "A \\ B", true},
+		{"{1, 2, 2}", "{2, 1}", true},
+		{"{1, 2}", "{1, 3}", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" = "+tt.b, func(t *testing.T) {
+			a, err := parser.ParseSet(tt.a)
+			if err != nil {
+				t.Fatalf("ParseSet(%q): %v", tt.a, err)
+			}
+			b, err := parser.ParseSet(tt.b)
+			if err != nil {
+				t.Fatalf("ParseSet(%q): %v", tt.b, err)
+			}
+			if got := ast.Equal(a, b); got != tt.want {
+				t.Errorf("Equal(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqualChains(t *testing.T) {
+	a, b, c := ast.NewVariableNode("a"), ast.NewVariableNode("b"), ast.NewVariableNode("c")
+	chain := func(operator lexer.BooleanTokenType, operands ...ast.ASTNode) ast.ASTNode {
+		node, err := ast.NewChainNode(operator, operands...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return node
+	}
+
+	tests := []struct {
+		x, y ast.ASTNode
+		want bool
+	}{
+		{chain(lexer.CONJ, a, b, c), chain(lexer.CONJ, c, a, b), true},
+		{chain(lexer.DISJ, a, b, c), chain(lexer.DISJ, b, c, a), true},
+		{chain(lexer.CONJ, a, b, c), chain(lexer.DISJ, a, b, c), false},
+		{chain(lexer.CONJ, a, a, b), chain(lexer.CONJ, a, b, b), false},
+		{chain(lexer.IMPL, a, b, c), chain(lexer.IMPL, c, b, a), false},
+	}
+
+	for _, tt := range tests {
+		if got := ast.Equal(tt.x, tt.y); got != tt.want {
+			t.Errorf("Equal(%s, %s) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+// colliding hides the hash of the node it wraps.
+type colliding struct {
+	ast.ASTNode
+}
+
+func (colliding) Hash() uint64 { return 0 }
+
+func TestEqualHashCollision(t *testing.T) {
+	a := ast.NewBinaryNode(lexer.CONJ, colliding{ast.NewVariableNode("a")}, colliding{ast.NewVariableNode("b")})
+	b := ast.NewBinaryNode(lexer.CONJ, colliding{ast.NewVariableNode("c")}, colliding{ast.NewVariableNode("d")})
+	if a.Hash() != b.Hash() {
+		t.Fatalf("hashes of %s and %s differ", a, b)
+	}
+	if ast.Equal(a, b) {
+		t.Errorf("Equal(%s, %s) = true for nodes that only share a hash", a, b)
+	}
+}
@@ -0,0 +1,260 @@
+package ast
+
+import (
+	"fmt"
+	"hash/fnv"
+	"logicka/lib/lexer"
+	"logicka/lib/utils"
+	"slices"
+	"strings"
+)
+
+// SetNode is an expression of the set language: a named set, a set literal or
+// an operation on sets.
+type SetNode interface {
+	ASTNode
+	setNode()
+}
+
+// SetNameNode refers to a set by name.
+type SetNameNode struct {
+	Spanned
+	Name string
+}
+
+func NewSetNameNode(name string) *SetNameNode {
+	return &SetNameNode{Name: name}
+}
+
+func (n *SetNameNode) setNode() {}
+
+func (n *SetNameNode) Equals(other ASTNode) bool {
+	node, ok := other.(*SetNameNode)
+	return ok && Equal(n, node)
+}
+
+func (n *SetNameNode) String() string {
+	return n.Name
+}
+
+func (n *SetNameNode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("set name"))
+	h.Write([]byte(n.Name))
+	return h.Sum64()
+}
+
+// SetLiteralNode lists the elements of a set, as in {a, b, c}.
+type SetLiteralNode struct {
+	Spanned
+	Elements []string
+}
+
+func NewSetLiteralNode(elements ...string) *SetLiteralNode {
+	return &SetLiteralNode{Elements: slices.Clone(elements)}
+}
+
+func (n *SetLiteralNode) setNode() {}
+
+func (n *SetLiteralNode) Equals(other ASTNode) bool {
+	node, ok := other.(*SetLiteralNode)
+	return ok && Equal(n, node)
+}
+
+func (n *SetLiteralNode) String() string {
+	return fmt.Sprintf("{%s}", strings.Join(n.Elements, ", "))
+}
+
+func (n *SetLiteralNode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("set literal"))
+
+	// Order and repetition of elements do not change the set
+	elements := slices.Clone(n.Elements)
+	slices.Sort(elements)
+	for _, element := range slices.Compact(elements) {
+		h.Write([]byte(element))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// SetBinaryNode represents a binary set operation.
+type SetBinaryNode struct {
+	Spanned
+	Operator    lexer.SetTokenType // UNION, ADD, INTERSECT, SYMDIFF or SUBSTRACT
+	Left, Right SetNode
+}
+
+func NewSetBinaryNode(operator lexer.SetTokenType, left, right SetNode) *SetBinaryNode {
+	return &SetBinaryNode{
+		Operator: operator,
+		Left:     left,
+		Right:    right,
+	}
+}
+
+func (n *SetBinaryNode) setNode() {}
+
+func (n *SetBinaryNode) Equals(other ASTNode) bool {
+	node, ok := other.(*SetBinaryNode)
+	return ok && Equal(n, node)
+}
+
+func (n *SetBinaryNode) Children() []ASTNode {
+	return []ASTNode{n.Left, n.Right}
+}
+
+func (n *SetBinaryNode) String() string {
+	return fmt.Sprintf("%s %s %s",
+		n.Left.String(),
+		n.Operator.String(),
+		n.Right.String())
+}
+
+func (n *SetBinaryNode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("set binary"))
+	h.Write([]byte(n.Operator.String()))
+
+	leftHash := n.Left.Hash()
+	rightHash := n.Right.Hash()
+
+	// Difference is the only operation where order matters
+	if n.Operator != lexer.SUBSTRACT && leftHash > rightHash {
+		leftHash, rightHash = rightHash, leftHash
+	}
+	h.Write(utils.Uint64ToBytes(leftHash))
+	h.Write(utils.Uint64ToBytes(rightHash))
+	return h.Sum64()
+}
+
+// SetGroupingNode represents a parenthesized set expression.
+type SetGroupingNode struct {
+	Spanned
+	Expr SetNode
+}
+
+func NewSetGroupingNode(expr SetNode) *SetGroupingNode {
+	return &SetGroupingNode{Expr: expr}
+}
+
+func (n *SetGroupingNode) setNode() {}
+
+func (n *SetGroupingNode) Equals(other ASTNode) bool {
+	node, ok := other.(*SetGroupingNode)
+	return ok && Equal(n, node)
+}
+
+func (n *SetGroupingNode) Children() []ASTNode {
+	return []ASTNode{n.Expr}
+}
+
+func (n *SetGroupingNode) String() string {
+	return fmt.Sprintf("(%s)", n.Expr.String())
+}
+
+func (n *SetGroupingNode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("set grouping"))
+	h.Write(utils.Uint64ToBytes(n.Expr.Hash()))
+	return h.Sum64()
+}
+
+// SetComplementNode represents the complement of a set relative to the universe.
+type SetComplementNode struct {
+	Spanned
+	Operand SetNode
+}
+
+func NewSetComplementNode(operand SetNode) *SetComplementNode {
+	return &SetComplementNode{Operand: operand}
+}
+
+func (n *SetComplementNode) setNode() {}
+
+func (n *SetComplementNode) Equals(other ASTNode) bool {
+	node, ok := other.(*SetComplementNode)
+	return ok && Equal(n, node)
+}
+
+func (n *SetComplementNode) Children() []ASTNode {
+	return []ASTNode{n.Operand}
+}
+
+func (n *SetComplementNode) String() string {
+	return fmt.Sprintf("%s'", n.Operand.String())
+}
+
+func (n *SetComplementNode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("set complement"))
+	h.Write(utils.Uint64ToBytes(n.Operand.Hash()))
+	return h.Sum64()
+}
+
+// SetDefinitionNode binds a name to a set expression, as in A = {a, b}.
+// It is a statement rather than an expression and is not a SetNode.
+type SetDefinitionNode struct {
+	Spanned
+	Name  string
+	Value SetNode
+}
+
+func NewSetDefinitionNode(name string, value SetNode) *SetDefinitionNode {
+	return &SetDefinitionNode{Name: name, Value: value}
+}
+
+func (n *SetDefinitionNode) Equals(other ASTNode) bool {
+	node, ok := other.(*SetDefinitionNode)
+	return ok && Equal(n, node)
+}
+
+func (n *SetDefinitionNode) Children() []ASTNode {
+	return []ASTNode{n.Value}
+}
+
+func (n *SetDefinitionNode) String() string {
+	return fmt.Sprintf("%s = %s", n.Name, n.Value.String())
+}
+
+func (n *SetDefinitionNode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("set definition"))
+	h.Write([]byte(n.Name))
+	h.Write(utils.Uint64ToBytes(n.Value.Hash()))
+	return h.Sum64()
+}
+
+// SetMembershipNode tests whether an element belongs to a set, as in a ∈ A.
+// Like SetDefinitionNode it is a statement and is not a SetNode.
+type SetMembershipNode struct {
+	Spanned
+	Element string
+	Set     SetNode
+}
+
+func NewSetMembershipNode(element string, set SetNode) *SetMembershipNode {
+	return &SetMembershipNode{Element: element, Set: set}
+}
+
+func (n *SetMembershipNode) Equals(other ASTNode) bool {
+	node, ok := other.(*SetMembershipNode)
+	return ok && Equal(n, node)
+}
+
+func (n *SetMembershipNode) Children() []ASTNode {
+	return []ASTNode{n.Set}
+}
+
+func (n *SetMembershipNode) String() string {
+	return fmt.Sprintf("%s ∈ %s", n.Element, n.Set.String())
+}
+
+func (n *SetMembershipNode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("set membership"))
+	h.Write([]byte(n.Element))
+	h.Write(utils.Uint64ToBytes(n.Set.Hash()))
+	return h.Sum64()
+}
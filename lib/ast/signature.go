@@ -0,0 +1,79 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"logicka/lib/lexer"
+)
+
+// Signature records the arity of every predicate and function symbol used in
+// a formula.
+type Signature struct {
+	Predicates map[string]int
+	Functions  map[string]int
+}
+
+// ArityError reports a symbol applied to a different number of arguments
+// than at its first use.
+type ArityError struct {
+	Kind     string // "predicate" or "function"
+	Name     string
+	Expected int        // number of arguments at the first use
+	Found    int        // number of arguments at this use
+	Span     lexer.Span // this use
+	First    lexer.Span // the first use
+}
+
+func (e ArityError) Error() string {
+	return fmt.Sprintf("%s %s is applied to %d and to %d arguments at positions %d and %d",
+		e.Kind, e.Name, e.Expected, e.Found, e.First.Start, e.Span.Start)
+}
+
+// SignatureOf collects the signature of node. The first use of a symbol fixes
+// its arity; every later use with another arity is reported as an ArityError,
+// all of them joined into the returned error.
+func SignatureOf(node ASTNode) (*Signature, error) {
+	signature := &Signature{
+		Predicates: make(map[string]int),
+		Functions:  make(map[string]int),
+	}
+	first := make(map[string]lexer.Span)
+	var errs []error
+
+	record := func(kind, name string, arity int, span lexer.Span, arities map[string]int) {
+		expected, ok := arities[name]
+		if !ok {
+			arities[name] = arity
+			first[kind+" "+name] = span
+			return
+		}
+		if expected != arity {
+			errs = append(errs, ArityError{
+				Kind:     kind,
+				Name:     name,
+				Expected: expected,
+				Found:    arity,
+				Span:     span,
+				First:    first[kind+" "+name],
+			})
+		}
+	}
+
+	var walk func(node ASTNode)
+	walk = func(node ASTNode) {
+		switch n := node.(type) {
+		case *PredicateNode:
+			record("predicate", n.Name, len(n.Args), n.Span(), signature.Predicates)
+		case *FunctionNode:
+			record("function", n.Name, len(n.Args), n.Span(), signature.Functions)
+		}
+		if traversable, ok := node.(Traversable); ok {
+			for _, child := range traversable.Children() {
+				walk(child)
+			}
+		}
+	}
+	walk(node)
+
+	return signature, errors.Join(errs...)
+}
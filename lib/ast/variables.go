@@ -0,0 +1,160 @@
+package ast
+
+import (
+	"logicka/lib/lexer"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// VariableClash is a variable that occurs free in a formula and is also bound
+// by one of its quantifiers, as x in P(x) ∧ ∀x Q(x).
+type VariableClash struct {
+	Name   string
+	Free   lexer.Span // the first free occurrence
+	Binder lexer.Span // the first quantifier that binds the name
+}
+
+// VariableAnalysis lists the free and bound variables of a formula, each in
+// order of first appearance, and the names that are both.
+type VariableAnalysis struct {
+	Free    []string
+	Bound   []string
+	Clashes []VariableClash
+}
+
+// AnalyzeVariables computes the free and bound variables of node and the
+// clashes between them.
+func AnalyzeVariables(node ASTNode) VariableAnalysis {
+	analysis := VariableAnalysis{Free: []string{}, Bound: []string{}, Clashes: []VariableClash{}}
+	free := make(map[string]lexer.Span)
+	binders := make(map[string]lexer.Span)
+
+	var walk func(node ASTNode, bound []string)
+	walk = func(node ASTNode, bound []string) {
+		switch n := node.(type) {
+		case *VariableNode:
+			if _, ok := free[n.Name]; !ok && !slices.Contains(bound, n.Name) {
+				free[n.Name] = n.Span()
+				analysis.Free = append(analysis.Free, n.Name)
+			}
+		case *QuantifierNode:
+			if _, ok := binders[n.Variable]; !ok {
+				binders[n.Variable] = n.Span()
+				analysis.Bound = append(analysis.Bound, n.Variable)
+			}
+			walk(n.Body, append(slices.Clone(bound), n.Variable))
+		case Traversable:
+			for _, child := range n.Children() {
+				walk(child, bound)
+			}
+		}
+	}
+	walk(node, nil)
+
+	for _, name := range analysis.Free {
+		if binder, ok := binders[name]; ok {
+			analysis.Clashes = append(analysis.Clashes, VariableClash{Name: name, Free: free[name], Binder: binder})
+		}
+	}
+	return analysis
+}
+
+// FreeVariables returns the variables of node that are not bound by an
+// enclosing quantifier, in order of first appearance.
+func FreeVariables(node ASTNode) []string {
+	return AnalyzeVariables(node).Free
+}
+
+// BoundVariables returns the variables bound by the quantifiers of node, in
+// order of first appearance.
+func BoundVariables(node ASTNode) []string {
+	return AnalyzeVariables(node).Bound
+}
+
+// Substitute returns a copy of node with every free occurrence of variable
+// replaced by term. A quantifier whose variable occurs free in term is
+// renamed first, ∀y P(x, y) with f(y) for x becoming ∀y1 P(f(y), y1), so
+// that no variable of term gets captured.
+func Substitute(node ASTNode, variable string, term ASTNode) ASTNode {
+	return substitute(node, variable, term, FreeVariables(term))
+}
+
+func substitute(node ASTNode, variable string, term ASTNode, termFree []string) ASTNode {
+	replace := func(node ASTNode) ASTNode {
+		return substitute(node, variable, term, termFree)
+	}
+	replaceAll := func(nodes []ASTNode) []ASTNode {
+		replaced := make([]ASTNode, len(nodes))
+		for i, n := range nodes {
+			replaced[i] = replace(n)
+		}
+		return replaced
+	}
+
+	switch n := node.(type) {
+	case *VariableNode:
+		if n.Name == variable {
+			return term
+		}
+		return n
+	case *GroupingNode:
+		return &GroupingNode{Spanned: n.Spanned, Expr: replace(n.Expr)}
+	case *BinaryNode:
+		return &BinaryNode{Spanned: n.Spanned, Operator: n.Operator, Left: replace(n.Left), Right: replace(n.Right)}
+	case *ChainNode:
+		return &ChainNode{Spanned: n.Spanned, Operator: n.Operator, Operands: replaceAll(n.Operands)}
+	case *UnaryNode:
+		return &UnaryNode{Spanned: n.Spanned, Operator: n.Operator, Operand: replace(n.Operand)}
+	case *PredicateNode:
+		return &PredicateNode{Spanned: n.Spanned, Name: n.Name, Args: replaceAll(n.Args)}
+	case *FunctionNode:
+		return &FunctionNode{Spanned: n.Spanned, Name: n.Name, Args: replaceAll(n.Args)}
+	case *QuantifierNode:
+		if n.Variable == variable || !slices.Contains(FreeVariables(n.Body), variable) {
+			return n
+		}
+		bound, body := n.Variable, n.Body
+		if slices.Contains(termFree, bound) {
+			bound = FreshVariable(bound, slices.Concat(termFree, VariableNames(body), []string{variable}))
+			body = renameFree(body, n.Variable, bound)
+		}
+		return &QuantifierNode{Spanned: n.Spanned, Type: n.Type, Variable: bound, Domain: n.Domain, Body: replace(body)}
+	default:
+		return node
+	}
+}
+
+// VariableNames returns every variable name of node, free, bound or used
+// by a quantifier.
+func VariableNames(node ASTNode) []string {
+	var names []string
+	var walk func(node ASTNode)
+	walk = func(node ASTNode) {
+		switch n := node.(type) {
+		case *VariableNode:
+			names = append(names, n.Name)
+		case *QuantifierNode:
+			names = append(names, n.Variable)
+		}
+		if traversable, ok := node.(Traversable); ok {
+			for _, child := range traversable.Children() {
+				walk(child)
+			}
+		}
+	}
+	walk(node)
+	return names
+}
+
+// FreshVariable returns name, with any trailing number replaced by the
+// smallest one that makes it differ from every name in taken.
+func FreshVariable(name string, taken []string) string {
+	base := strings.TrimRight(name, "0123456789")
+	for i := 1; ; i++ {
+		fresh := base + strconv.Itoa(i)
+		if !slices.Contains(taken, fresh) {
+			return fresh
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ast_test
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/parser"
+	"slices"
+	"testing"
+)
+
+func TestSubstitute(t *testing.T) {
+	tests := []struct {
+		name     string
+		formula  string
+		variable string
+		term     string
+		want     string
+	}{
+		{"free occurrence", "P(x) ∧ Q(y)", "x", "f(a)", "P(f(a)) ∧ Q(y)"},
+		{"captured term", "∀y P(x)", "x", "f(y)", "∀y1 P(f(y))"},
+		{"captured term, bound variable used", "∀y P(x, y)", "x", "f(y)", "∀y1 P(f(y), y1)"},
+		{"shadowed target", "∀x P(x)", "x", "f(y)", "∀x P(x)"},
+		{"shadowed in part", "P(x) ∧ ∃x Q(x)", "x", "a", "P(a) ∧ ∃x Q(x)"},
+		{"nested binders", "∀y ∃z R(x, y, z)", "x", "g(y, z)", "∀y1 ∃z1 R(g(y, z), y1, z1)"},
+		{"fresh name taken", "∀y P(x, y, y1)", "x", "y", "∀y2 P(y, y2, y1)"},
+		{"no occurrence", "∀y P(y)", "x", "y", "∀y P(y)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formula, err := parser.Parse(tt.formula)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.formula, err)
+			}
+			term, err := parser.ParseTerm(tt.term)
+			if err != nil {
+				t.Fatalf("ParseTerm(%q): %v", tt.term, err)
+			}
+			if got := ast.Substitute(formula, tt.variable, term).String(); got != tt.want {
+				t.Errorf("Substitute(%s, %s, %s) = %s, want %s", tt.formula, tt.variable, tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeVariables(t *testing.T) {
+	tests := []struct {
+		formula string
+		free    []string
+		bound   []string
+		clashes int
+	}{
+		{"P(x, y)", []string{"x", "y"}, nil, 0},
+		{"∀x P(x, y)", []string{"y"}, []string{"x"}, 0},
+		{"P(x) ∧ ∀x Q(x)", []string{"x"}, []string{"x"}, 1},
+		{"∀x ∃y R(x, y)", nil, []string{"x", "y"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			formula, err := parser.Parse(tt.formula)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.formula, err)
+			}
+			analysis := ast.AnalyzeVariables(formula)
+			if !slices.Equal(analysis.Free, tt.free) || !slices.Equal(analysis.Bound, tt.bound) || len(analysis.Clashes) != tt.clashes {
+				t.Errorf("AnalyzeVariables(%s) = free %v, bound %v, %d clashes, want free %v, bound %v, %d clashes",
+					tt.formula, analysis.Free, analysis.Bound, len(analysis.Clashes), tt.free, tt.bound, tt.clashes)
+			}
+		})
+	}
+}
+
+func TestFreshVariable(t *testing.T) {
+	tests := []struct {
+		name  string
+		taken []string
+		want  string
+	}{
+		{"x", nil, "x1"},
+		{"x", []string{"x1", "x2"}, "x3"},
+		{"x7", []string{"x1"}, "x2"},
+	}
+	for _, tt := range tests {
+		if got := ast.FreshVariable(tt.name, tt.taken); got != tt.want {
+			t.Errorf("FreshVariable(%s, %v) = %s, want %s", tt.name, tt.taken, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package fol
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/simplification/rules/base"
+	"logicka/lib/visitor"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// literal is an atomic formula or its negation. Propositional variables are
+// predicates without arguments.
+type literal struct {
+	negated   bool
+	predicate string
+	args      []term
+}
+
+func (l literal) String() string {
+	atom := l.predicate
+	if len(l.args) > 0 {
+		atom = term{name: l.predicate, args: l.args}.String()
+	}
+	if l.negated {
+		return lexer.NEG.String() + atom
+	}
+	return atom
+}
+
+func (l literal) apply(s substitution) literal {
+	args := make([]term, len(l.args))
+	for i, arg := range l.args {
+		args[i] = s.apply(arg)
+	}
+	return literal{negated: l.negated, predicate: l.predicate, args: args}
+}
+
+// clause is a disjunction of literals; the empty clause is false.
+type clause []literal
+
+func (c clause) String() string {
+	if len(c) == 0 {
+		return "□"
+	}
+	literals := make([]string, len(c))
+	for i, l := range c {
+		literals[i] = l.String()
+	}
+	return strings.Join(literals, " "+lexer.DISJ.String()+" ")
+}
+
+// apply applies s to every literal and drops the literals that become
+// duplicates.
+func (c clause) apply(s substitution) clause {
+	result := make(clause, 0, len(c))
+	seen := make(map[string]bool)
+	for _, l := range c {
+		l = l.apply(s)
+		if key := l.String(); !seen[key] {
+			seen[key] = true
+			result = append(result, l)
+		}
+	}
+	return result
+}
+
+// variables returns the variables of c in order of first appearance.
+func (c clause) variables() []string {
+	var variables []string
+	var walk func(t term)
+	walk = func(t term) {
+		if t.variable && !slices.Contains(variables, t.name) {
+			variables = append(variables, t.name)
+		}
+		for _, arg := range t.args {
+			walk(arg)
+		}
+	}
+	for _, l := range c {
+		for _, arg := range l.args {
+			walk(arg)
+		}
+	}
+	return variables
+}
+
+// key identifies c up to the names of its variables and the order of its
+// literals.
+func (c clause) key() string {
+	renaming := make(substitution)
+	for i, variable := range c.variables() {
+		renaming[variable] = term{name: "#" + strconv.Itoa(i)}
+	}
+	literals := make([]string, len(c))
+	for i, l := range c {
+		literals[i] = l.apply(renaming).String()
+	}
+	slices.Sort(literals)
+	return strings.Join(literals, "|")
+}
+
+// tautology reports whether c contains a literal and its negation.
+func (c clause) tautology() bool {
+	for i, l := range c {
+		for _, other := range c[i+1:] {
+			if l.negated != other.negated && l.predicate == other.predicate &&
+				slices.Equal(argStrings(l.args), argStrings(other.args)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func argStrings(args []term) []string {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		strs[i] = arg.String()
+	}
+	return strs
+}
+
+// clauseForm negates formula, Skolemizes the negation and returns its
+// matrix in conjunctive normal form as a list of clauses, together with the
+// steps of the conversion. The formula is valid exactly when the clauses are
+// unsatisfiable.
+func clauseForm(formula ast.ASTNode) (string, []clause, []base.RuleApplication, error) {
+	negated := ast.NewUnaryNode(lexer.NEG, ast.NewGroupingNode(formula))
+	negated.SetSpan(formula.Span())
+
+	skolemized, steps, err := visitor.NewSkolemizer().Skolemize(negated)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	// What is left of the quantifier prefix binds the variables of the clauses.
+	var universal []string
+	matrix := skolemized
+	for {
+		if grouping, ok := matrix.(*ast.GroupingNode); ok {
+			matrix = grouping.Expr
+			continue
+		}
+		q, ok := matrix.(*ast.QuantifierNode)
+		if !ok {
+			break
+		}
+		universal = append(universal, q.Variable)
+		matrix = q.Body
+	}
+
+	c := &converter{universal: universal}
+	clauses, err := c.cnf(matrix, false)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return skolemized.String(), clauses, steps, nil
+}
+
+type converter struct {
+	universal []string
+}
+
+// cnf returns the clauses of node, or of its negation when negated is set.
+// Negations are pushed to the atoms by De Morgan's laws and disjunctions are
+// distributed over conjunctions.
+func (c *converter) cnf(node ast.ASTNode, negated bool) ([]clause, error) {
+	switch n := node.(type) {
+	case *ast.GroupingNode:
+		return c.cnf(n.Expr, negated)
+	case *ast.UnaryNode:
+		if n.Operator != lexer.NEG {
+			return nil, visitor.OperatorError{Operator: n.Operator.String(), Span: n.Span()}
+		}
+		return c.cnf(n.Operand, !negated)
+	case *ast.LiteralNode:
+		if n.Value != negated {
+			return []clause{}, nil
+		}
+		return []clause{{}}, nil
+	case *ast.VariableNode:
+		return []clause{{literal{negated: negated, predicate: n.Name}}}, nil
+	case *ast.PredicateNode:
+		args := make([]term, len(n.Args))
+		for i, arg := range n.Args {
+			t, err := c.term(arg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = t
+		}
+		return []clause{{literal{negated: negated, predicate: n.Name, args: args}}}, nil
+	case *ast.BinaryNode:
+		return c.combine(n.Operator, []ast.ASTNode{n.Left, n.Right}, negated, n.Span())
+	case *ast.ChainNode:
+		return c.combine(n.Operator, n.Operands, negated, n.Span())
+	default:
+		return nil, visitor.NodeTypeError{NodeType: fmt.Sprintf("%T", n)}
+	}
+}
+
+func (c *converter) combine(operator lexer.BooleanTokenType, operands []ast.ASTNode, negated bool, span lexer.Span) ([]clause, error) {
+	if operator != lexer.CONJ && operator != lexer.DISJ {
+		return nil, visitor.OperatorError{Operator: operator.String(), Span: span}
+	}
+	conjunction := (operator == lexer.CONJ) != negated
+
+	result := []clause{{}}
+	if conjunction {
+		result = []clause{}
+	}
+	for _, operand := range operands {
+		clauses, err := c.cnf(operand, negated)
+		if err != nil {
+			return nil, err
+		}
+		if conjunction {
+			result = append(result, clauses...)
+			continue
+		}
+		// (a ∧ b) ∨ (c ∧ d) ≡ (a ∨ c) ∧ (a ∨ d) ∧ (b ∨ c) ∧ (b ∨ d)
+		var product []clause
+		for _, left := range result {
+			for _, right := range clauses {
+				product = append(product, slices.Concat(left, right).apply(nil))
+			}
+		}
+		result = product
+	}
+	return result, nil
+}
+
+func (c *converter) term(node ast.ASTNode) (term, error) {
+	switch n := node.(type) {
+	case *ast.VariableNode:
+		return term{name: n.Name, variable: slices.Contains(c.universal, n.Name)}, nil
+	case *ast.FunctionNode:
+		args := make([]term, len(n.Args))
+		for i, arg := range n.Args {
+			t, err := c.term(arg)
+			if err != nil {
+				return term{}, err
+			}
+			args[i] = t
+		}
+		return term{name: n.Name, args: args}, nil
+	default:
+		return term{}, visitor.NodeTypeError{NodeType: fmt.Sprintf("%T", n)}
+	}
+}
@@ -0,0 +1,418 @@
+// Package fol holds decision procedures for first-order formulas that work
+// on whole formulas rather than node by node.
+package fol
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/visitor"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxSearchSteps bounds the number of partial interpretations a counter-model
+// search looks at before giving up.
+const MaxSearchSteps = 1 << 20
+
+// DefaultMaxDomain is the largest domain FindCounterModel tries when none is
+// given.
+const DefaultMaxDomain = 3
+
+// SearchLimitError is returned when a search runs out of steps.
+type SearchLimitError struct {
+	Steps      int
+	DomainSize int
+}
+
+func (e SearchLimitError) Error() string {
+	return fmt.Sprintf("search gave up after %d steps on domains of size %d", e.Steps, e.DomainSize)
+}
+
+// RelationTable lists a predicate on every tuple of the domain, for display.
+type RelationTable struct {
+	Name  string
+	Arity int
+	Rows  []RelationRow
+}
+
+type RelationRow struct {
+	Args  []string
+	Holds bool
+}
+
+// CounterModel is an interpretation in which a formula is false. Formula-level
+// variables are read as propositions, free term variables as constants.
+type CounterModel struct {
+	Structure    visitor.Structure
+	Propositions map[string]bool
+	Relations    []RelationTable
+	Witnesses    []visitor.QuantifierWitness // why the formula is false
+}
+
+// SearchResult is the outcome of a counter-model search. CounterModel is nil
+// when the formula holds in every interpretation with at most MaxDomain
+// elements, which suggests, but does not prove, that it is valid.
+type SearchResult struct {
+	Formula      string
+	MaxDomain    int
+	CounterModel *CounterModel
+}
+
+// FindCounterModel looks for the smallest interpretation that falsifies
+// formula, trying domains of 1 to maxDomain elements. Interpretations are
+// built one choice at a time: a truth value of a predicate on a tuple, the
+// value of a function or constant, membership in a set. After each choice the
+// formula is evaluated in three-valued logic, and a branch is dropped as soon
+// as the formula is true in all of its completions. Constants are given
+// elements in order of first use, so that interpretations that only differ by
+// renaming elements are not tried twice. A maxDomain of zero or less means
+// DefaultMaxDomain.
+func FindCounterModel(formula ast.ASTNode, maxDomain int) (*SearchResult, error) {
+	if maxDomain <= 0 {
+		maxDomain = DefaultMaxDomain
+	}
+
+	signature, err := ast.SignatureOf(formula)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := collectSymbols(formula)
+	result := &SearchResult{Formula: formula.String(), MaxDomain: maxDomain}
+
+	for size := 1; size <= maxDomain; size++ {
+		s := newSearch(formula, signature, symbols, size)
+		found, err := s.run(0)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result.CounterModel, err = s.counterModel(signature)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+	}
+
+	return result, nil
+}
+
+// symbols lists the names of a formula that need an interpretation.
+type symbols struct {
+	propositions []string
+	constants    []string
+	sets         []string
+}
+
+func collectSymbols(formula ast.ASTNode) symbols {
+	var result symbols
+	add := func(list *[]string, name string) {
+		if !slices.Contains(*list, name) {
+			*list = append(*list, name)
+		}
+	}
+
+	var walkTerm func(node ast.ASTNode, bound []string)
+	walkTerm = func(node ast.ASTNode, bound []string) {
+		switch n := node.(type) {
+		case *ast.VariableNode:
+			if !slices.Contains(bound, n.Name) {
+				add(&result.constants, n.Name)
+			}
+		case *ast.FunctionNode:
+			for _, arg := range n.Args {
+				walkTerm(arg, bound)
+			}
+		}
+	}
+
+	// truthValued holds the variables of the enclosing quantifiers over truth
+	// values, which are not part of the interpretation.
+	var walk func(node ast.ASTNode, bound, truthValued []string)
+	walk = func(node ast.ASTNode, bound, truthValued []string) {
+		switch n := node.(type) {
+		case *ast.VariableNode:
+			if !slices.Contains(truthValued, n.Name) {
+				add(&result.propositions, n.Name)
+			}
+		case *ast.PredicateNode:
+			for _, arg := range n.Args {
+				walkTerm(arg, bound)
+			}
+		case *ast.QuantifierNode:
+			if set, ok := n.Domain.(*ast.SetNameNode); ok {
+				add(&result.sets, set.Name)
+			}
+			bound := append(slices.Clone(bound), n.Variable)
+			truthValued := slices.DeleteFunc(slices.Clone(truthValued), func(name string) bool { return name == n.Variable })
+			if n.Propositional() {
+				truthValued = append(truthValued, n.Variable)
+			}
+			walk(n.Body, bound, truthValued)
+		case ast.Traversable:
+			for _, child := range n.Children() {
+				walk(child, bound, truthValued)
+			}
+		}
+	}
+	walk(formula, nil, nil)
+
+	return result
+}
+
+type cellKind int
+
+const (
+	propositionCell cellKind = iota
+	constantCell
+	setCell
+	predicateCell
+	functionCell
+)
+
+// cell is one choice of the search: the value of a symbol on some arguments.
+type cell struct {
+	kind cellKind
+	name string
+	args []string
+}
+
+type search struct {
+	formula   ast.ASTNode
+	model     *interpretation
+	evaluator *partialEvaluator
+	cells     []cell
+	steps     int
+}
+
+func newSearch(formula ast.ASTNode, signature *ast.Signature, symbols symbols, size int) *search {
+	domain := make([]string, size)
+	for i := range domain {
+		domain[i] = strconv.Itoa(i + 1)
+	}
+
+	model := &interpretation{
+		domain:       domain,
+		propositions: make(map[string]bool),
+		constants:    make(map[string]string),
+		relations:    make(map[string]map[string]bool),
+		tables:       make(map[string]map[string]string),
+		sets:         make(map[string]map[string]bool),
+	}
+
+	var cells []cell
+	for _, name := range symbols.propositions {
+		cells = append(cells, cell{kind: propositionCell, name: name})
+	}
+	for _, name := range symbols.constants {
+		cells = append(cells, cell{kind: constantCell, name: name})
+	}
+	for _, name := range symbols.sets {
+		model.sets[name] = make(map[string]bool)
+		for _, element := range domain {
+			cells = append(cells, cell{kind: setCell, name: name, args: []string{element}})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(signature.Predicates)) {
+		model.relations[name] = make(map[string]bool)
+		for _, tuple := range tuples(domain, signature.Predicates[name]) {
+			cells = append(cells, cell{kind: predicateCell, name: name, args: tuple})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(signature.Functions)) {
+		model.tables[name] = make(map[string]string)
+		for _, tuple := range tuples(domain, signature.Functions[name]) {
+			cells = append(cells, cell{kind: functionCell, name: name, args: tuple})
+		}
+	}
+
+	return &search{
+		formula:   formula,
+		model:     model,
+		evaluator: &partialEvaluator{model: model},
+		cells:     cells,
+	}
+}
+
+// run extends the interpretation from the i-th cell on and reports whether
+// it found a completion in which the formula is false. When it does, the
+// interpretation is left in place.
+func (s *search) run(i int) (bool, error) {
+	s.steps++
+	if s.steps > MaxSearchSteps {
+		return false, SearchLimitError{Steps: MaxSearchSteps, DomainSize: len(s.model.domain)}
+	}
+
+	value, err := s.evaluator.evaluate(s.formula)
+	if err != nil {
+		return false, err
+	}
+	if value != unknown || i == len(s.cells) {
+		return value == falsity, nil
+	}
+
+	c := s.cells[i]
+	for _, candidate := range s.candidates(c) {
+		s.assign(c, candidate)
+		found, err := s.run(i + 1)
+		if found || err != nil {
+			return found, err
+		}
+	}
+	s.unassign(c)
+	return false, nil
+}
+
+// candidates returns the values a cell can take. A constant only gets an
+// element already used by an earlier constant or the first unused one: all
+// other elements are interchangeable at that point, since constants are
+// chosen before anything else that mentions elements.
+func (s *search) candidates(c cell) []string {
+	switch c.kind {
+	case constantCell:
+		used := len(slices.Compact(slices.Sorted(maps.Values(s.model.constants))))
+		return s.model.domain[:min(used+1, len(s.model.domain))]
+	case functionCell:
+		return s.model.domain
+	default:
+		return []string{"false", "true"}
+	}
+}
+
+func (s *search) assign(c cell, value string) {
+	switch c.kind {
+	case propositionCell:
+		s.model.propositions[c.name] = value == "true"
+	case constantCell:
+		s.model.constants[c.name] = value
+	case setCell:
+		s.model.sets[c.name][c.args[0]] = value == "true"
+	case predicateCell:
+		s.model.relations[c.name][tupleKey(c.args)] = value == "true"
+	case functionCell:
+		s.model.tables[c.name][tupleKey(c.args)] = value
+	}
+}
+
+func (s *search) unassign(c cell) {
+	switch c.kind {
+	case propositionCell:
+		delete(s.model.propositions, c.name)
+	case constantCell:
+		delete(s.model.constants, c.name)
+	case setCell:
+		delete(s.model.sets[c.name], c.args[0])
+	case predicateCell:
+		delete(s.model.relations[c.name], tupleKey(c.args))
+	case functionCell:
+		delete(s.model.tables[c.name], tupleKey(c.args))
+	}
+}
+
+// counterModel fills in the choices the search did not need to make and
+// turns the interpretation into a structure.
+func (s *search) counterModel(signature *ast.Signature) (*CounterModel, error) {
+	for _, c := range s.cells {
+		if !s.assigned(c) {
+			s.assign(c, s.candidates(c)[0])
+		}
+	}
+
+	m := s.model
+	structure := visitor.Structure{
+		Domain:     m.domain,
+		Predicates: make(map[string][][]string),
+		Functions:  make(map[string][]visitor.FunctionMapping),
+		Constants:  maps.Clone(m.constants),
+		Sets:       make(map[string][]string),
+	}
+	var relations []RelationTable
+
+	for _, name := range slices.Sorted(maps.Keys(signature.Predicates)) {
+		table := RelationTable{Name: name, Arity: signature.Predicates[name]}
+		structure.Predicates[name] = [][]string{}
+		for _, tuple := range tuples(m.domain, table.Arity) {
+			holds := m.relations[name][tupleKey(tuple)]
+			table.Rows = append(table.Rows, RelationRow{Args: tuple, Holds: holds})
+			if holds {
+				structure.Predicates[name] = append(structure.Predicates[name], tuple)
+			}
+		}
+		relations = append(relations, table)
+	}
+	for _, name := range slices.Sorted(maps.Keys(signature.Functions)) {
+		for _, tuple := range tuples(m.domain, signature.Functions[name]) {
+			structure.Functions[name] = append(structure.Functions[name], visitor.FunctionMapping{
+				Args:  tuple,
+				Value: m.tables[name][tupleKey(tuple)],
+			})
+		}
+	}
+	for name, members := range m.sets {
+		structure.Sets[name] = []string{}
+		for _, element := range m.domain {
+			if members[element] {
+				structure.Sets[name] = append(structure.Sets[name], element)
+			}
+		}
+	}
+
+	// Evaluate the formula once more with the ordinary solver, which explains
+	// the result with witnesses.
+	ctx := &visitor.EvaluationContext{Variables: m.propositions, Structure: &structure}
+	table, err := visitor.NewBooleanSolver(ctx).Solve(s.formula)
+	if err != nil {
+		return nil, err
+	}
+	var witnesses []visitor.QuantifierWitness
+	if len(table) > 0 {
+		witnesses = table[0].Witnesses
+	}
+
+	return &CounterModel{
+		Structure:    structure,
+		Propositions: maps.Clone(m.propositions),
+		Relations:    relations,
+		Witnesses:    witnesses,
+	}, nil
+}
+
+func (s *search) assigned(c cell) bool {
+	var ok bool
+	switch c.kind {
+	case propositionCell:
+		_, ok = s.model.propositions[c.name]
+	case constantCell:
+		_, ok = s.model.constants[c.name]
+	case setCell:
+		_, ok = s.model.sets[c.name][c.args[0]]
+	case predicateCell:
+		_, ok = s.model.relations[c.name][tupleKey(c.args)]
+	case functionCell:
+		_, ok = s.model.tables[c.name][tupleKey(c.args)]
+	}
+	return ok
+}
+
+// tuples returns every tuple of the given length over domain, in
+// lexicographic order.
+func tuples(domain []string, arity int) [][]string {
+	result := [][]string{{}}
+	for range arity {
+		var next [][]string
+		for _, tuple := range result {
+			for _, element := range domain {
+				next = append(next, append(slices.Clone(tuple), element))
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+func tupleKey(elements []string) string {
+	return strings.Join(elements, "\x00")
+}
@@ -0,0 +1,155 @@
+package fol
+
+import (
+	"errors"
+	"logicka/lib/ast"
+	"logicka/lib/parser"
+	"logicka/lib/visitor"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindCounterModel(t *testing.T) {
+	tests := []struct {
+		input string
+		size  int // the size of the smallest counter-model, 0 when there is none
+	}{
+		{"p → q", 1},
+		{"p ∨ !p", 0},
+		{"∀x P(x) → P(a)", 0},
+		{"P(a) → ∀x P(x)", 2},
+		{"∃x P(x) → P(a)", 2},
+		{"P(a) → P(b)", 2},
+		{"∀x ∃y R(x, y) → ∃y ∀x R(x, y)", 2},
+		{"∃y ∀x R(x, y) → ∀x ∃y R(x, y)", 0},
+		{"∀x P(f(x)) → P(f(a))", 0},
+		{"P(f(a)) → P(a)", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			result, err := FindCounterModel(node, 3)
+			if err != nil {
+				t.Fatalf("FindCounterModel(%q): %v", tt.input, err)
+			}
+
+			model := result.CounterModel
+			if tt.size == 0 {
+				if model != nil {
+					t.Fatalf("FindCounterModel(%q) found %v, want none", tt.input, model.Structure)
+				}
+				return
+			}
+			if model == nil {
+				t.Fatalf("FindCounterModel(%q) found none", tt.input)
+			}
+			if len(model.Structure.Domain) != tt.size {
+				t.Errorf("counter-model of size %d, want %d", len(model.Structure.Domain), tt.size)
+			}
+			checkCounterModel(t, node, model)
+		})
+	}
+}
+
+// checkCounterModel evaluates node in model with the ordinary solver.
+func checkCounterModel(t *testing.T, node ast.ASTNode, model *CounterModel) {
+	t.Helper()
+	signature, err := ast.SignatureOf(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := model.Structure.Check(signature); err != nil {
+		t.Fatalf("counter-model does not interpret the formula: %v", err)
+	}
+
+	ctx := &visitor.EvaluationContext{Variables: model.Propositions, Structure: &model.Structure}
+	table, err := visitor.NewBooleanSolver(ctx).Solve(node)
+	if err != nil {
+		t.Fatalf("evaluating in the counter-model: %v", err)
+	}
+	if len(table) != 1 || table[0].Result {
+		t.Errorf("formula is not false in the counter-model: %v", table)
+	}
+}
+
+func TestFindCounterModelConstantOrder(t *testing.T) {
+	node, err := parser.Parse("P(b) ∨ !P(a) ∨ !P(c)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := FindCounterModel(node, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The constants get elements in order of first use, so b comes first.
+	constants := result.CounterModel.Structure.Constants
+	if constants["b"] != "1" || constants["a"] != "2" || constants["c"] != "2" {
+		t.Errorf("constants = %v, want b = 1, a = c = 2", constants)
+	}
+}
+
+func TestFindCounterModelDefaultDomain(t *testing.T) {
+	// The counter-model needs two elements.
+	node, err := parser.Parse("∃x P(x) → ∀x P(x)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, maxDomain := range []int{0, -1} {
+		result, err := FindCounterModel(node, maxDomain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.MaxDomain != DefaultMaxDomain || result.CounterModel == nil || len(result.CounterModel.Structure.Domain) != 2 {
+			t.Errorf("FindCounterModel with maxDomain %d = %+v, want a counter-model of 2 elements", maxDomain, result)
+		}
+	}
+}
+
+func TestCandidatesBreakSymmetry(t *testing.T) {
+	node, err := parser.Parse("P(a) ∧ P(b) ∧ P(c)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := ast.SignatureOf(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newSearch(node, signature, collectSymbols(node), 3)
+
+	want := [][]string{{"1"}, {"1", "2"}, {"1", "2", "3"}}
+	values := []string{"1", "2"}
+	for i, c := range s.cells[:3] {
+		if got := s.candidates(c); !slices.Equal(got, want[i]) {
+			t.Errorf("candidates for %s = %v, want %v", c.name, got, want[i])
+		}
+		if i < len(values) {
+			s.assign(c, values[i])
+		}
+	}
+}
+
+func TestFindCounterModelSearchLimit(t *testing.T) {
+	// The value of a long exclusive or is unknown until every proposition is
+	// chosen, so no branch is cut off early.
+	var propositions []string
+	for i := range 21 {
+		propositions = append(propositions, "p"+string(rune('a'+i)))
+	}
+	xor := strings.Join(propositions, " ⊕ ")
+	node, err := parser.Parse("(" + xor + ") ∨ !(" + xor + ")")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = FindCounterModel(node, 1)
+	var limit SearchLimitError
+	if !errors.As(err, &limit) || limit.Steps != MaxSearchSteps || limit.DomainSize != 1 {
+		t.Fatalf("FindCounterModel = %v, want a SearchLimitError on domains of size 1", err)
+	}
+}
@@ -0,0 +1,287 @@
+package fol
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/visitor"
+	"maps"
+	"slices"
+)
+
+// truth is a value of Kleene's three-valued logic. unknown stands for a
+// value that depends on parts of the interpretation not chosen yet.
+type truth int8
+
+const (
+	unknown truth = iota
+	falsity
+	verity
+)
+
+func truthOf(b bool) truth {
+	if b {
+		return verity
+	}
+	return falsity
+}
+
+func not(a truth) truth {
+	switch a {
+	case verity:
+		return falsity
+	case falsity:
+		return verity
+	default:
+		return unknown
+	}
+}
+
+func and(a, b truth) truth {
+	switch {
+	case a == falsity || b == falsity:
+		return falsity
+	case a == verity && b == verity:
+		return verity
+	default:
+		return unknown
+	}
+}
+
+func or(a, b truth) truth {
+	return not(and(not(a), not(b)))
+}
+
+func equal(a, b truth) truth {
+	if a == unknown || b == unknown {
+		return unknown
+	}
+	return truthOf(a == b)
+}
+
+// interpretation is a partial interpretation of the symbols of a formula.
+// Missing entries are not chosen yet.
+type interpretation struct {
+	domain       []string
+	propositions map[string]bool
+	constants    map[string]string
+	relations    map[string]map[string]bool   // predicate -> tuple key -> holds
+	tables       map[string]map[string]string // function -> tuple key -> value
+	sets         map[string]map[string]bool   // set -> element -> member
+}
+
+// partialEvaluator evaluates a formula in a partial interpretation. Whatever
+// depends on unchosen symbols evaluates to unknown, so a known result holds
+// in every completion of the interpretation.
+type partialEvaluator struct {
+	model    *interpretation
+	bindings map[string]string
+}
+
+func (e *partialEvaluator) evaluate(node ast.ASTNode) (truth, error) {
+	e.bindings = make(map[string]string)
+	return visitor.Accept[truth](node, e)
+}
+
+func (e *partialEvaluator) VisitGrouping(node *ast.GroupingNode) (truth, error) {
+	return visitor.Accept[truth](node.Expr, e)
+}
+
+func (e *partialEvaluator) VisitLiteral(node *ast.LiteralNode) (truth, error) {
+	return truthOf(node.Value), nil
+}
+
+func (e *partialEvaluator) VisitVariable(node *ast.VariableNode) (truth, error) {
+	value, ok := e.model.propositions[node.Name]
+	if !ok {
+		return unknown, nil
+	}
+	return truthOf(value), nil
+}
+
+func (e *partialEvaluator) VisitBinary(node *ast.BinaryNode) (truth, error) {
+	left, err := visitor.Accept[truth](node.Left, e)
+	if err != nil {
+		return unknown, err
+	}
+	right, err := visitor.Accept[truth](node.Right, e)
+	if err != nil {
+		return unknown, err
+	}
+
+	switch node.Operator {
+	case lexer.IMPL:
+		return or(not(left), right), nil
+	case lexer.CONV:
+		return or(left, not(right)), nil
+	case lexer.EQUIV:
+		return equal(left, right), nil
+	case lexer.CONJ:
+		return and(left, right), nil
+	case lexer.DISJ:
+		return or(left, right), nil
+	case lexer.XOR:
+		return not(equal(left, right)), nil
+	case lexer.NAND:
+		return not(and(left, right)), nil
+	case lexer.NOR:
+		return not(or(left, right)), nil
+	default:
+		return unknown, visitor.OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+}
+
+func (e *partialEvaluator) VisitChain(node *ast.ChainNode) (truth, error) {
+	result := truthOf(node.Operator == lexer.CONJ)
+	for _, operand := range node.Operands {
+		value, err := visitor.Accept[truth](operand, e)
+		if err != nil {
+			return unknown, err
+		}
+		switch node.Operator {
+		case lexer.CONJ:
+			result = and(result, value)
+		case lexer.DISJ:
+			result = or(result, value)
+		default:
+			return unknown, visitor.OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+		}
+	}
+	return result, nil
+}
+
+func (e *partialEvaluator) VisitUnary(node *ast.UnaryNode) (truth, error) {
+	operand, err := visitor.Accept[truth](node.Operand, e)
+	if err != nil {
+		return unknown, err
+	}
+	if node.Operator != lexer.NEG {
+		return unknown, visitor.OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+	return not(operand), nil
+}
+
+func (e *partialEvaluator) VisitPredicate(node *ast.PredicateNode) (truth, error) {
+	args := make([]string, len(node.Args))
+	for i, arg := range node.Args {
+		element, known, err := e.evaluateTerm(arg)
+		if err != nil || !known {
+			return unknown, err
+		}
+		args[i] = element
+	}
+
+	holds, ok := e.model.relations[node.Name][tupleKey(args)]
+	if !ok {
+		return unknown, nil
+	}
+	return truthOf(holds), nil
+}
+
+// VisitQuantifier treats ∀x ∈ S φ as ∀x (S(x) → φ) and ∃x ∈ S φ as
+// ∃x (S(x) ∧ φ), with membership in S chosen like a unary predicate.
+func (e *partialEvaluator) VisitQuantifier(node *ast.QuantifierNode) (truth, error) {
+	if node.Propositional() && slices.Contains(ast.FreeVariables(node.Body), node.Variable) {
+		return e.quantifyTruthValues(node)
+	}
+
+	outer := e.bindings
+	defer func() { e.bindings = outer }()
+
+	forall := node.Type == lexer.FORALL
+	result := truthOf(forall)
+
+	for _, element := range e.model.domain {
+		e.bindings = maps.Clone(outer)
+		e.bindings[node.Variable] = element
+
+		value, err := visitor.Accept[truth](node.Body, e)
+		if err != nil {
+			return unknown, err
+		}
+
+		if node.Domain != nil {
+			set, ok := node.Domain.(*ast.SetNameNode)
+			if !ok {
+				return unknown, visitor.NodeTypeError{NodeType: fmt.Sprintf("%T", node.Domain)}
+			}
+			member := unknown
+			if m, ok := e.model.sets[set.Name][element]; ok {
+				member = truthOf(m)
+			}
+			if forall {
+				value = or(not(member), value)
+			} else {
+				value = and(member, value)
+			}
+		}
+
+		if forall {
+			result = and(result, value)
+		} else {
+			result = or(result, value)
+		}
+		// A single counterexample or witness decides the quantifier.
+		if result == truthOf(!forall) {
+			return result, nil
+		}
+	}
+	return result, nil
+}
+
+// quantifyTruthValues evaluates a quantifier over truth values by fixing its
+// variable to true and to false in turn.
+func (e *partialEvaluator) quantifyTruthValues(node *ast.QuantifierNode) (truth, error) {
+	outer := e.model.propositions
+	defer func() { e.model.propositions = outer }()
+
+	forall := node.Type == lexer.FORALL
+	result := truthOf(forall)
+	for _, value := range []bool{true, false} {
+		e.model.propositions = maps.Clone(outer)
+		e.model.propositions[node.Variable] = value
+
+		body, err := visitor.Accept[truth](node.Body, e)
+		if err != nil {
+			return unknown, err
+		}
+		if forall {
+			result = and(result, body)
+		} else {
+			result = or(result, body)
+		}
+		if result == truthOf(!forall) {
+			return result, nil
+		}
+	}
+	return result, nil
+}
+
+func (e *partialEvaluator) VisitFunction(node *ast.FunctionNode) (truth, error) {
+	return unknown, visitor.TermError{Term: node.String(), Span: node.Span()}
+}
+
+// evaluateTerm returns the element a term denotes and whether it is known yet.
+func (e *partialEvaluator) evaluateTerm(node ast.ASTNode) (string, bool, error) {
+	switch n := node.(type) {
+	case *ast.VariableNode:
+		if element, ok := e.bindings[n.Name]; ok {
+			return element, true, nil
+		}
+		element, ok := e.model.constants[n.Name]
+		return element, ok, nil
+	case *ast.FunctionNode:
+		args := make([]string, len(n.Args))
+		for i, arg := range n.Args {
+			element, known, err := e.evaluateTerm(arg)
+			if err != nil || !known {
+				return "", false, err
+			}
+			args[i] = element
+		}
+		element, ok := e.model.tables[n.Name][tupleKey(args)]
+		return element, ok, nil
+	default:
+		return "", false, visitor.NodeTypeError{NodeType: fmt.Sprintf("%T", n)}
+	}
+}
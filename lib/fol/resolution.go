@@ -0,0 +1,333 @@
+package fol
+
+import (
+	"cmp"
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/simplification/rules/base"
+	"maps"
+	"slices"
+)
+
+// DefaultResolutionSteps is the step limit of Prove when none is given.
+const DefaultResolutionSteps = 2000
+
+// ResolutionLimitError is returned when the prover derives as many clauses
+// as its step limit allows without finding a refutation.
+type ResolutionLimitError struct {
+	Steps int
+}
+
+func (e ResolutionLimitError) Error() string {
+	return fmt.Sprintf("no refutation found within %d steps", e.Steps)
+}
+
+// Rules a clause of a proof can be obtained by.
+const (
+	InputRule      = "Дизъюнкт отрицания формулы"
+	ResolutionRule = "Резолюция"
+	FactoringRule  = "Склейка"
+)
+
+// ProofClause is a clause of a proof. Parents are the IDs of the clauses it
+// was derived from. Before a resolution step the variables of the second
+// parent that also occur in the first are renamed as Renaming says; Unifier
+// is the most general unifier that was applied, restricted to the variables
+// of the parents after renaming.
+type ProofClause struct {
+	ID       int
+	Clause   string
+	Rule     string
+	Parents  []int
+	Renaming map[string]string
+	Unifier  map[string]string
+}
+
+// Proof is the outcome of a resolution proof of a formula. Negation is its
+// Skolemized negation, Steps the derivation of it and Clauses its clause
+// form. When Proved is set, Refutation lists the clauses that lead to the
+// empty clause, parents before children, numbered from 1; otherwise the
+// clauses were saturated without a contradiction and the formula is not valid.
+type Proof struct {
+	Formula    string
+	Negation   string
+	Steps      []base.RuleApplication
+	Clauses    []string
+	Proved     bool
+	Refutation []ProofClause
+}
+
+// Prove tries to show that formula is valid by refuting its negation with
+// binary resolution and factoring. Clauses are taken shortest first; a new
+// clause is dropped when it is a tautology or an instance of a clause already
+// kept. At most maxSteps clauses are derived.
+func Prove(formula ast.ASTNode, maxSteps int) (*Proof, error) {
+	if maxSteps <= 0 {
+		maxSteps = DefaultResolutionSteps
+	}
+
+	negation, clauses, steps, err := clauseForm(formula)
+	if err != nil {
+		return nil, err
+	}
+	proof := &Proof{Formula: formula.String(), Negation: negation, Steps: steps, Clauses: []string{}}
+	for _, c := range clauses {
+		proof.Clauses = append(proof.Clauses, c.String())
+	}
+
+	p := &prover{maxSteps: maxSteps, keys: make(map[string]bool)}
+	for _, c := range clauses {
+		if empty := p.add(derived{clause: c, rule: InputRule}); empty != nil {
+			proof.Proved, proof.Refutation = true, p.refutation(empty)
+			return proof, nil
+		}
+	}
+
+	empty, err := p.saturate()
+	if err != nil {
+		return nil, err
+	}
+	if empty != nil {
+		proof.Proved, proof.Refutation = true, p.refutation(empty)
+	}
+	return proof, nil
+}
+
+// derived is a clause together with how it was obtained.
+type derived struct {
+	id       int
+	clause   clause
+	rule     string
+	parents  []int
+	unifier  map[string]string
+	renaming map[string]string
+
+	texts  []string // the literals of clause as text
+	ground []bool   // whether each literal is free of variables
+}
+
+type prover struct {
+	maxSteps  int
+	steps     int
+	all       []*derived // every clause by ID
+	queue     []*derived // clauses not processed yet
+	processed []*derived
+	keys      map[string]bool
+}
+
+// saturate runs the given-clause loop until the empty clause is derived or
+// no clause is left to process.
+func (p *prover) saturate() (*derived, error) {
+	for len(p.queue) > 0 {
+		given := p.next()
+		if slices.ContainsFunc(p.processed, func(d *derived) bool { return d.subsumes(given) }) {
+			continue
+		}
+		p.processed = append(p.processed, given)
+
+		for _, factor := range factors(given) {
+			if empty, err := p.derive(factor); empty != nil || err != nil {
+				return empty, err
+			}
+		}
+		for _, other := range p.processed {
+			for _, resolvent := range resolvents(given, other) {
+				if empty, err := p.derive(resolvent); empty != nil || err != nil {
+					return empty, err
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// next removes the shortest clause from the queue, the oldest among equals.
+func (p *prover) next() *derived {
+	i := 0
+	for j, d := range p.queue {
+		if len(d.clause) < len(p.queue[i].clause) {
+			i = j
+		}
+	}
+	given := p.queue[i]
+	p.queue = slices.Delete(p.queue, i, i+1)
+	return given
+}
+
+func (p *prover) derive(d derived) (*derived, error) {
+	p.steps++
+	if p.steps > p.maxSteps {
+		return nil, ResolutionLimitError{Steps: p.maxSteps}
+	}
+	return p.add(d), nil
+}
+
+// add records a clause and queues it unless it is redundant. It returns the
+// recorded clause when it is empty.
+func (p *prover) add(d derived) *derived {
+	d.texts = make([]string, len(d.clause))
+	d.ground = make([]bool, len(d.clause))
+	for i, l := range d.clause {
+		d.texts[i] = l.String()
+		d.ground[i] = len(clause{l}.variables()) == 0
+	}
+
+	key := d.clause.key()
+	if p.keys[key] || d.clause.tautology() ||
+		slices.ContainsFunc(p.processed, func(kept *derived) bool { return kept.subsumes(&d) }) {
+		return nil
+	}
+	p.keys[key] = true
+
+	d.id = len(p.all)
+	p.all = append(p.all, &d)
+	if len(d.clause) == 0 {
+		return &d
+	}
+	p.queue = append(p.queue, &d)
+	return nil
+}
+
+// subsumes reports whether some instance of d is contained in other, which
+// makes other redundant.
+func (d *derived) subsumes(other *derived) bool {
+	if len(d.clause) > len(other.clause) {
+		return false
+	}
+	var search func(i int, s substitution) bool
+	search = func(i int, s substitution) bool {
+		if i == len(d.clause) {
+			return true
+		}
+		pattern := d.clause[i]
+		for j, target := range other.clause {
+			if pattern.negated != target.negated || pattern.predicate != target.predicate {
+				continue
+			}
+			// A literal without variables only matches itself.
+			if d.ground[i] {
+				if d.texts[i] == other.texts[j] && search(i+1, s) {
+					return true
+				}
+				continue
+			}
+			extended := maps.Clone(s)
+			if extended.matchAll(pattern.args, target.args) && search(i+1, extended) {
+				return true
+			}
+		}
+		return false
+	}
+	return search(0, make(substitution))
+}
+
+// refutation collects the ancestors of the empty clause and renumbers them.
+func (p *prover) refutation(empty *derived) []ProofClause {
+	needed := make(map[int]bool)
+	var visit func(id int)
+	visit = func(id int) {
+		if needed[id] {
+			return
+		}
+		needed[id] = true
+		for _, parent := range p.all[id].parents {
+			visit(parent)
+		}
+	}
+	visit(empty.id)
+
+	ids := make(map[int]int)
+	var result []ProofClause
+	for _, d := range p.all {
+		if !needed[d.id] {
+			continue
+		}
+		ids[d.id] = len(result) + 1
+
+		parents := make([]int, len(d.parents))
+		for i, parent := range d.parents {
+			parents[i] = ids[parent]
+		}
+		result = append(result, ProofClause{
+			ID:       ids[d.id],
+			Clause:   d.clause.String(),
+			Rule:     d.rule,
+			Parents:  parents,
+			Renaming: d.renaming,
+			Unifier:  d.unifier,
+		})
+	}
+	return result
+}
+
+// factors returns the clauses obtained from d by unifying two of its
+// literals of the same sign.
+func factors(d *derived) []derived {
+	var result []derived
+	for i, l := range d.clause {
+		for _, other := range d.clause[i+1:] {
+			if l.negated != other.negated || l.predicate != other.predicate {
+				continue
+			}
+			unifier, ok := unify(l.args, other.args, nil)
+			if !ok {
+				continue
+			}
+			result = append(result, derived{
+				clause:  d.clause.apply(unifier),
+				rule:    FactoringRule,
+				parents: []int{d.id},
+				unifier: unifier.restrict(d.clause.variables()),
+			})
+		}
+	}
+	return result
+}
+
+// resolvents returns the binary resolvents of a and b. The variables of b
+// are renamed apart from those of a first, as every clause is implicitly
+// universally quantified on its own.
+func resolvents(a, b *derived) []derived {
+	complementary := func(l literal) bool {
+		return slices.ContainsFunc(b.clause, func(m literal) bool {
+			return l.negated != m.negated && l.predicate == m.predicate
+		})
+	}
+	if !slices.ContainsFunc(a.clause, complementary) {
+		return nil
+	}
+
+	renaming := make(substitution)
+	taken := a.clause.variables()
+	for _, variable := range b.clause.variables() {
+		if slices.Contains(taken, variable) {
+			fresh := ast.FreshVariable(variable, slices.Concat(taken, b.clause.variables()))
+			renaming[variable] = term{name: fresh, variable: true}
+			taken = append(taken, fresh)
+		}
+	}
+	other := b.clause.apply(renaming)
+
+	var result []derived
+	for i, l := range a.clause {
+		for j, m := range other {
+			if l.negated == m.negated || l.predicate != m.predicate {
+				continue
+			}
+			unifier, ok := unify(l.args, m.args, nil)
+			if !ok {
+				continue
+			}
+			resolvent := slices.Concat(slices.Delete(slices.Clone(a.clause), i, i+1), slices.Delete(slices.Clone(other), j, j+1))
+			result = append(result, derived{
+				clause:   resolvent.apply(unifier),
+				rule:     ResolutionRule,
+				parents:  []int{a.id, b.id},
+				unifier:  unifier.restrict(slices.Concat(a.clause.variables(), other.variables())),
+				renaming: renaming.restrict(b.clause.variables()),
+			})
+		}
+	}
+	slices.SortStableFunc(result, func(x, y derived) int { return cmp.Compare(len(x.clause), len(y.clause)) })
+	return result
+}
@@ -0,0 +1,183 @@
+package fol
+
+import (
+	"errors"
+	"logicka/lib/parser"
+	"slices"
+	"testing"
+)
+
+func TestProve(t *testing.T) {
+	tests := []struct {
+		input  string
+		proved bool
+	}{
+		{"p ∨ !p", true},
+		{"p → q", false},
+		{"∀x P(x) → P(a)", true},
+		{"P(a) → ∀x P(x)", false},
+		{"∀x (P(x) → Q(x)) ∧ P(a) → Q(a)", true},
+		{"∀x (P(x) → Q(x)) ∧ Q(a) → P(a)", false},
+		{"∃x (P(x) → ∀y P(y))", true},
+		{"∀x ∃y R(x, y) → ∃y ∀x R(x, y)", false},
+		{"∃y ∀x R(x, y) → ∀x ∃y R(x, y)", true},
+		{"∀x (P(x) ∨ P(f(x))) → ∃x P(x)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			proof, err := Prove(node, 0)
+			if err != nil {
+				t.Fatalf("Prove(%q): %v", tt.input, err)
+			}
+			if proof.Proved != tt.proved {
+				t.Fatalf("Prove(%q).Proved = %v, want %v", tt.input, proof.Proved, tt.proved)
+			}
+			if tt.proved {
+				checkRefutation(t, proof)
+			} else if len(proof.Refutation) != 0 {
+				t.Errorf("Prove(%q) has a refutation without a proof", tt.input)
+			}
+		})
+	}
+}
+
+// checkRefutation checks that the refutation is numbered from 1, that every
+// clause comes after its parents and that it ends in the empty clause.
+func checkRefutation(t *testing.T, proof *Proof) {
+	t.Helper()
+	refutation := proof.Refutation
+	if len(refutation) == 0 {
+		t.Fatal("proof without a refutation")
+	}
+	for i, c := range refutation {
+		if c.ID != i+1 {
+			t.Errorf("clause %d has ID %d", i+1, c.ID)
+		}
+		switch c.Rule {
+		case InputRule:
+			if len(c.Parents) != 0 || !slices.Contains(proof.Clauses, c.Clause) {
+				t.Errorf("input clause %d: %s with parents %v is not a clause of the negation", c.ID, c.Clause, c.Parents)
+			}
+		case ResolutionRule, FactoringRule:
+			want := 2
+			if c.Rule == FactoringRule {
+				want = 1
+			}
+			if len(c.Parents) != want {
+				t.Errorf("%s clause %d has parents %v", c.Rule, c.ID, c.Parents)
+			}
+			for _, parent := range c.Parents {
+				if parent < 1 || parent >= c.ID {
+					t.Errorf("clause %d has parent %d", c.ID, parent)
+				}
+			}
+		default:
+			t.Errorf("clause %d has rule %q", c.ID, c.Rule)
+		}
+	}
+	if last := refutation[len(refutation)-1]; last.Clause != "□" {
+		t.Errorf("refutation ends in %s, want □", last.Clause)
+	}
+}
+
+func TestProveStepLimit(t *testing.T) {
+	node, err := parser.Parse("∀x (P(x) → P(f(x))) ∧ P(a) → P(b)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Prove(node, 50)
+	var limit ResolutionLimitError
+	if !errors.As(err, &limit) || limit.Steps != 50 {
+		t.Fatalf("Prove = %v, want a ResolutionLimitError after 50 steps", err)
+	}
+}
+
+func variable(name string) term {
+	return term{name: name, variable: true}
+}
+
+func constant(name string) term {
+	return term{name: name}
+}
+
+// prepared returns c as the prover records it.
+func prepared(c clause) *derived {
+	p := &prover{maxSteps: 1, keys: make(map[string]bool)}
+	p.add(derived{clause: c, rule: InputRule})
+	return p.all[0]
+}
+
+func TestFactors(t *testing.T) {
+	d := prepared(clause{
+		{predicate: "P", args: []term{variable("x")}},
+		{predicate: "P", args: []term{constant("a")}},
+		{negated: true, predicate: "P", args: []term{constant("b")}},
+	})
+
+	var got []string
+	for _, factor := range factors(d) {
+		got = append(got, factor.clause.String())
+		if factor.rule != FactoringRule || factor.unifier["x"] != "a" {
+			t.Errorf("factor %s by %s with unifier %v", factor.clause, factor.rule, factor.unifier)
+		}
+	}
+	if want := []string{"P(a) ∨ !P(b)"}; !slices.Equal(got, want) {
+		t.Errorf("factors = %v, want %v", got, want)
+	}
+}
+
+func TestResolventsRenameApart(t *testing.T) {
+	a := prepared(clause{
+		{predicate: "P", args: []term{variable("x"), constant("a")}},
+	})
+	b := prepared(clause{
+		{negated: true, predicate: "P", args: []term{constant("b"), variable("x")}},
+		{predicate: "Q", args: []term{variable("x")}},
+	})
+
+	// Without renaming x could not be both b and a.
+	result := resolvents(a, b)
+	if len(result) != 1 {
+		t.Fatalf("resolvents = %d clauses, want 1", len(result))
+	}
+	r := result[0]
+	if r.clause.String() != "Q(a)" {
+		t.Errorf("resolvent = %s, want Q(a)", r.clause)
+	}
+	if fresh, ok := r.renaming["x"]; !ok || fresh == "x" {
+		t.Errorf("renaming = %v, want x renamed", r.renaming)
+	}
+}
+
+func TestSubsumes(t *testing.T) {
+	general := prepared(clause{{predicate: "P", args: []term{variable("x")}}})
+	instance := prepared(clause{
+		{predicate: "P", args: []term{constant("a")}},
+		{predicate: "Q", args: []term{constant("b")}},
+	})
+	ground := prepared(clause{{predicate: "P", args: []term{constant("a")}}})
+	pair := prepared(clause{{predicate: "R", args: []term{variable("x"), variable("x")}}})
+	mixed := prepared(clause{{predicate: "R", args: []term{constant("a"), constant("b")}}})
+
+	tests := []struct {
+		name     string
+		d, other *derived
+		want     bool
+	}{
+		{"instance", general, instance, true},
+		{"ground", ground, instance, true},
+		{"more general", instance, general, false},
+		{"not an instance", ground, general, false},
+		{"repeated variable", pair, mixed, false},
+	}
+	for _, tt := range tests {
+		if got := tt.d.subsumes(tt.other); got != tt.want {
+			t.Errorf("%s: %s subsumes %s = %v, want %v", tt.name, tt.d.clause, tt.other.clause, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package fol
+
+import (
+	"maps"
+	"strings"
+)
+
+// term is a first-order term: a variable, a constant or a function applied
+// to terms. Only universally quantified variables of a clause are variables;
+// free variables of the original formula and Skolem constants are constants.
+type term struct {
+	name     string
+	args     []term
+	variable bool
+}
+
+func (t term) String() string {
+	if len(t.args) == 0 {
+		return t.name
+	}
+	var b strings.Builder
+	t.write(&b)
+	return b.String()
+}
+
+func (t term) write(b *strings.Builder) {
+	b.WriteString(t.name)
+	if len(t.args) == 0 {
+		return
+	}
+	b.WriteString("(")
+	for i, arg := range t.args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		arg.write(b)
+	}
+	b.WriteString(")")
+}
+
+// substitution maps variables to terms. Bindings may refer to variables bound
+// by other bindings; apply follows them to the end.
+type substitution map[string]term
+
+func (s substitution) apply(t term) term {
+	if t.variable {
+		if bound, ok := s[t.name]; ok {
+			return s.apply(bound)
+		}
+		return t
+	}
+	if len(t.args) == 0 {
+		return t
+	}
+	args := make([]term, len(t.args))
+	for i, arg := range t.args {
+		args[i] = s.apply(arg)
+	}
+	return term{name: t.name, args: args}
+}
+
+// restrict returns the values s gives to variables, as text.
+func (s substitution) restrict(variables []string) map[string]string {
+	result := make(map[string]string)
+	for _, variable := range variables {
+		if _, ok := s[variable]; ok {
+			result[variable] = s.apply(term{name: variable, variable: true}).String()
+		}
+	}
+	return result
+}
+
+// unify extends s to a most general unifier of the argument lists a and b.
+// It reports false when there is none; s itself is never modified.
+func unify(a, b []term, s substitution) (substitution, bool) {
+	if len(a) != len(b) {
+		return nil, false
+	}
+	s = maps.Clone(s)
+	if s == nil {
+		s = make(substitution)
+	}
+	for i := range a {
+		if !s.unify(a[i], b[i]) {
+			return nil, false
+		}
+	}
+	return s, true
+}
+
+func (s substitution) unify(a, b term) bool {
+	a, b = s.walk(a), s.walk(b)
+	switch {
+	case a.variable && b.variable && a.name == b.name:
+		return true
+	case a.variable:
+		return s.bind(a.name, b)
+	case b.variable:
+		return s.bind(b.name, a)
+	case a.name != b.name || len(a.args) != len(b.args):
+		return false
+	}
+	for i := range a.args {
+		if !s.unify(a.args[i], b.args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// bind binds variable to t unless t contains it, which would make the
+// unifier infinite.
+func (s substitution) bind(variable string, t term) bool {
+	if s.occurs(variable, t) {
+		return false
+	}
+	s[variable] = t
+	return true
+}
+
+func (s substitution) occurs(variable string, t term) bool {
+	t = s.walk(t)
+	if t.variable {
+		return t.name == variable
+	}
+	for _, arg := range t.args {
+		if s.occurs(variable, arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// walk follows the bindings of a variable until it reaches a term that is
+// not a bound variable.
+func (s substitution) walk(t term) term {
+	for t.variable {
+		bound, ok := s[t.name]
+		if !ok {
+			break
+		}
+		t = bound
+	}
+	return t
+}
+
+// matchAll matches the terms of patterns against those of terms pairwise.
+func (s substitution) matchAll(patterns, terms []term) bool {
+	if len(patterns) != len(terms) {
+		return false
+	}
+	for i := range patterns {
+		if !s.match(patterns[i], terms[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// match extends s so that pattern with s applied equals t, binding only
+// variables of pattern. It is the one-sided unification used by subsumption.
+func (s substitution) match(pattern, t term) bool {
+	if pattern.variable {
+		if bound, ok := s[pattern.name]; ok {
+			return bound.String() == t.String()
+		}
+		s[pattern.name] = t
+		return true
+	}
+	if t.variable || pattern.name != t.name || len(pattern.args) != len(t.args) {
+		return false
+	}
+	for i := range pattern.args {
+		if !s.match(pattern.args[i], t.args[i]) {
+			return false
+		}
+	}
+	return true
+}
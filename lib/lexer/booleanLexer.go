@@ -1,25 +1,32 @@
 package lexer
 
 import (
-	"fmt"
+	"errors"
 	"unicode"
 )
 
 type BooleanTokenType int
 
 const (
-	LPAREN BooleanTokenType = iota // (
-	RPAREN                         // )
-	FORALL                         // A
-	EXISTS                         // E
-	IMPL                           // ->
-	EQUIV                          // ~
-	CONJ                           // &
-	DISJ                           // \/
-	NEG                            // ! -
-	PRED
-	VAR
-	LIT // 1 0
+	LPAREN  BooleanTokenType = iota // (
+	RPAREN                          // )
+	FORALL                          // A ∀
+	EXISTS                          // E ∃
+	IMPL                            // -> → implies
+	CONV                            // <- ← (converse implication)
+	EQUIV                           // ~ <-> ↔ iff
+	XOR                             // ^ ⊕ xor
+	CONJ                            // & ∧ and
+	NAND                            // | ↑ nand (Sheffer stroke)
+	DISJ                            // \/ ∨ or
+	NOR                             // ↓ nor (Peirce arrow)
+	NEG                             // ! - ¬ not
+	PRED                            // identifier starting with an uppercase letter
+	VAR                             // any other identifier
+	LIT                             // 1 0 ⊤ ⊥ true false
+	DELIM                           // , between arguments
+	MEMBER                          // ∈ in (bounds a quantifier to a set)
+	ILLEGAL                         // rune that does not start any token
 )
 
 func (t BooleanTokenType) String() string {
@@ -29,17 +36,25 @@ func (t BooleanTokenType) String() string {
 	case RPAREN:
 		return ")"
 	case FORALL:
-		return "A"
+		return "∀"
 	case EXISTS:
-		return "E"
+		return "∃"
 	case IMPL:
 		return "→"
+	case CONV:
+		return "←"
 	case EQUIV:
 		return "~"
+	case XOR:
+		return "⊕"
 	case CONJ:
 		return "∧"
+	case NAND:
+		return "↑"
 	case DISJ:
 		return "∨"
+	case NOR:
+		return "↓"
 	case NEG:
 		return "!"
 	case PRED:
@@ -48,6 +63,12 @@ func (t BooleanTokenType) String() string {
 		return "VARIABLE"
 	case LIT:
 		return "LITERAL"
+	case DELIM:
+		return ","
+	case MEMBER:
+		return "∈"
+	case ILLEGAL:
+		return "ILLEGAL"
 	case EOF:
 		return "EOF"
 	default:
@@ -55,6 +76,17 @@ func (t BooleanTokenType) String() string {
 	}
 }
 
+// IsCommutative reports whether swapping the operands of a binary operator
+// leaves its value unchanged.
+func (t BooleanTokenType) IsCommutative() bool {
+	switch t {
+	case EQUIV, XOR, CONJ, NAND, DISJ, NOR:
+		return true
+	default:
+		return false
+	}
+}
+
 type BooleanLexer struct {
 	input []rune
 	pos   int
@@ -64,65 +96,88 @@ func NewBooleanLexer(input string) *BooleanLexer {
 	return &BooleanLexer{[]rune(input), 0}
 }
 
+// Lex tokenises the whole input. It does not stop at the first bad rune:
+// every rune that cannot start a token is emitted as an ILLEGAL token and
+// reported in the returned error, which joins one UnexpectedRuneError per rune.
 func (l *BooleanLexer) Lex() ([]Token[BooleanTokenType], error) {
 	var tokens []Token[BooleanTokenType]
+	var errs []error
 
 	for l.pos < len(l.input) {
 		if unicode.IsSpace(l.input[l.pos]) {
 			l.pos++
 			continue
 		}
+		startPos := l.pos
 		token, err := l.NextToken()
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
+			l.pos = startPos + 1
+			token = Token[BooleanTokenType]{Type: ILLEGAL, Value: string(l.input[startPos]), Pos: startPos}
 		}
 		tokens = append(tokens, token)
 	}
 
-	return tokens, nil
+	return tokens, errors.Join(errs...)
 }
 
 func (l *BooleanLexer) NextToken() (Token[BooleanTokenType], error) {
 	r := l.input[l.pos]
-	startPos := l.pos
 
 	switch r {
 	case '(':
-		l.pos++
-		return Token[BooleanTokenType]{Type: LPAREN, Value: "(", Pos: startPos}, nil
+		return l.lexRune(LPAREN), nil
 	case ')':
-		l.pos++
-		return Token[BooleanTokenType]{Type: RPAREN, Value: ")", Pos: startPos}, nil
-	case 'A':
-		l.pos++
-		return Token[BooleanTokenType]{Type: FORALL, Value: "A", Pos: startPos}, nil
-	case 'E':
-		l.pos++
-		return Token[BooleanTokenType]{Type: EXISTS, Value: "E", Pos: startPos}, nil
+		return l.lexRune(RPAREN), nil
+	case '∀':
+		return l.lexRune(FORALL), nil
+	case '∃':
+		return l.lexRune(EXISTS), nil
 	case '-':
 		return l.lexImpl()
-	case '!':
-		l.pos++
-		return Token[BooleanTokenType]{Type: NEG, Value: "!", Pos: startPos}, nil
-	case '~':
-		l.pos++
-		return Token[BooleanTokenType]{Type: EQUIV, Value: "~", Pos: startPos}, nil
-	case '&':
-		l.pos++
-		return Token[BooleanTokenType]{Type: CONJ, Value: "&", Pos: startPos}, nil
+	case '<':
+		return l.lexLeftArrow()
+	case '→':
+		return l.lexRune(IMPL), nil
+	case '←':
+		return l.lexRune(CONV), nil
+	case '!', '¬':
+		return l.lexRune(NEG), nil
+	case '~', '↔':
+		return l.lexRune(EQUIV), nil
+	case '&', '∧':
+		return l.lexRune(CONJ), nil
+	case '∨':
+		return l.lexRune(DISJ), nil
+	case '^', '⊕':
+		return l.lexRune(XOR), nil
+	case '|', '↑':
+		return l.lexRune(NAND), nil
+	case '↓':
+		return l.lexRune(NOR), nil
 	case '\\':
 		return l.lexDisj()
-	case '1', '0':
-		l.pos++
-		return Token[BooleanTokenType]{Type: LIT, Value: string(r), Pos: startPos}, nil
+	case '1', '0', '⊤', '⊥':
+		return l.lexRune(LIT), nil
+	case ',':
+		return l.lexRune(DELIM), nil
+	case '∈':
+		return l.lexRune(MEMBER), nil
 	default:
-		if unicode.IsLetter(r) {
+		if isIdentifierStart(r) {
 			return l.lexIdentifier()
 		}
-		return Token[BooleanTokenType]{}, UnexpectedRuneError{pos: l.pos, r: r}
+		return Token[BooleanTokenType]{}, UnexpectedRuneError{Rune: r, Pos: l.pos}
 	}
 }
 
+// lexRune emits a token of the given type made of the current rune.
+func (l *BooleanLexer) lexRune(tokenType BooleanTokenType) Token[BooleanTokenType] {
+	startPos := l.pos
+	l.pos++
+	return Token[BooleanTokenType]{Type: tokenType, Value: string(l.input[startPos]), Pos: startPos}
+}
+
 func (l *BooleanLexer) lexImpl() (Token[BooleanTokenType], error) {
 	startPos := l.pos
 	if l.pos+1 < len(l.input) && l.input[l.pos+1] == '>' {
@@ -133,6 +188,20 @@ func (l *BooleanLexer) lexImpl() (Token[BooleanTokenType], error) {
 	return Token[BooleanTokenType]{Type: NEG, Value: "-", Pos: startPos}, nil
 }
 
+// lexLeftArrow обрабатывает обратную импликацию (<-) и эквивалентность (<->)
+func (l *BooleanLexer) lexLeftArrow() (Token[BooleanTokenType], error) {
+	startPos := l.pos
+	if l.pos+1 < len(l.input) && l.input[l.pos+1] == '-' {
+		if l.pos+2 < len(l.input) && l.input[l.pos+2] == '>' {
+			l.pos += 3
+			return Token[BooleanTokenType]{Type: EQUIV, Value: "<->", Pos: startPos}, nil
+		}
+		l.pos += 2
+		return Token[BooleanTokenType]{Type: CONV, Value: "<-", Pos: startPos}, nil
+	}
+	return Token[BooleanTokenType]{}, UnexpectedRuneError{Rune: l.input[startPos], Pos: startPos}
+}
+
 // lexDisj обрабатывает дизъюнкцию (\/)
 func (l *BooleanLexer) lexDisj() (Token[BooleanTokenType], error) {
 	startPos := l.pos
@@ -140,16 +209,69 @@ func (l *BooleanLexer) lexDisj() (Token[BooleanTokenType], error) {
 		l.pos += 2
 		return Token[BooleanTokenType]{Type: DISJ, Value: "\\/", Pos: startPos}, nil
 	}
-	return Token[BooleanTokenType]{}, fmt.Errorf("expected '\\/' at position %d", startPos)
+	return Token[BooleanTokenType]{}, UnexpectedRuneError{Rune: l.input[startPos], Pos: startPos}
 }
 
+// lexIdentifier reads an identifier: a letter or underscore followed by any
+// number of letters, digits and underscores.
+//
+// Identifiers starting with an uppercase letter are predicates (P, Even, Loves),
+// everything else is a variable (x, ready, p_2). The single letters A and E are
+// reserved for the quantifiers, so a predicate needs at least one more character
+// to start with them (Ack, Eq). The word forms listed in keywords are reserved
+// as well and never become variables.
 func (l *BooleanLexer) lexIdentifier() (Token[BooleanTokenType], error) {
-	r := l.input[l.pos]
 	startPos := l.pos
-	l.pos++
+	for l.pos < len(l.input) && isIdentifierPart(l.input[l.pos]) {
+		l.pos++
+	}
+	value := string(l.input[startPos:l.pos])
+
+	if tokenType, ok := keywords[value]; ok {
+		return Token[BooleanTokenType]{Type: tokenType, Value: value, Pos: startPos}, nil
+	}
+
+	switch {
+	case value == "A":
+		return Token[BooleanTokenType]{Type: FORALL, Value: value, Pos: startPos}, nil
+	case value == "E":
+		return Token[BooleanTokenType]{Type: EXISTS, Value: value, Pos: startPos}, nil
+	case unicode.IsUpper(l.input[startPos]):
+		return Token[BooleanTokenType]{Type: PRED, Value: value, Pos: startPos}, nil
+	default:
+		return Token[BooleanTokenType]{Type: VAR, Value: value, Pos: startPos}, nil
+	}
+}
+
+// keywords maps the word forms of operators and constants to their token types.
+var keywords = map[string]BooleanTokenType{
+	"and":     CONJ,
+	"or":      DISJ,
+	"not":     NEG,
+	"implies": IMPL,
+	"iff":     EQUIV,
+	"xor":     XOR,
+	"nand":    NAND,
+	"nor":     NOR,
+	"true":    LIT,
+	"false":   LIT,
+	"in":      MEMBER,
+}
 
-	if unicode.IsLower(r) {
-		return Token[BooleanTokenType]{Type: VAR, Value: string(r), Pos: startPos}, nil
+// LiteralValue reports the boolean value denoted by the text of a LIT token.
+func LiteralValue(value string) bool {
+	switch value {
+	case "1", "⊤", "true":
+		return true
+	default:
+		return false
 	}
-	return Token[BooleanTokenType]{Type: PRED, Value: string(r), Pos: startPos}, nil
+}
+
+func isIdentifierStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || unicode.IsDigit(r)
 }
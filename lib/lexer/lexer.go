@@ -1,6 +1,9 @@
 package lexer
 
-import "fmt"
+import (
+	"fmt"
+	"unicode/utf8"
+)
 
 const EOF = -1
 
@@ -10,13 +13,56 @@ type Token[T any] struct {
 	Pos   int
 }
 
+// Span returns the rune range the token occupies in the source.
+func (t Token[T]) Span() Span {
+	return Span{Start: t.Pos, End: t.Pos + utf8.RuneCountInString(t.Value)}
+}
+
+// Span is a half-open range [Start, End) of rune offsets in the source text.
+type Span struct {
+	Start int
+	End   int
+}
+
+// IsZero reports whether the span is unset, i.e. the node or token it belongs
+// to was not read from the source.
+func (s Span) IsZero() bool {
+	return s == Span{}
+}
+
+// Cover returns the smallest span containing both s and other. An unset span
+// does not contribute.
+func (s Span) Cover(other Span) Span {
+	if s.IsZero() {
+		return other
+	}
+	if other.IsZero() {
+		return s
+	}
+	return Span{Start: min(s.Start, other.Start), End: max(s.End, other.End)}
+}
+
+// ByteSpan converts a rune span over input into the matching byte span.
+func (s Span) ByteSpan(input string) Span {
+	return Span{Start: byteOffset(input, s.Start), End: byteOffset(input, s.End)}
+}
+
+func byteOffset(input string, runeOffset int) int {
+	offset := 0
+	for i := 0; i < runeOffset && offset < len(input); i++ {
+		_, size := utf8.DecodeRuneInString(input[offset:])
+		offset += size
+	}
+	return offset
+}
+
 type UnexpectedRuneError struct {
-	r   rune
-	pos int
+	Rune rune
+	Pos  int
 }
 
 func (e UnexpectedRuneError) Error() string {
-	return fmt.Sprintf("unexpected rune %c on pos %d", e.r, e.pos)
+	return fmt.Sprintf("unexpected rune %c on pos %d", e.Rune, e.Pos)
 }
 
 type Lexer[T any] interface {
@@ -1,22 +1,29 @@
 package lexer
 
-import "unicode"
+import (
+	"errors"
+	"unicode"
+)
 
 type SetTokenType int
 
 const (
-	LBRACE    SetTokenType = iota // {
-	RBRACE                        // }
-	NAME                          // Uppercase single letter
-	ASSIGN                        // =
-	ELEMENT                       // Lowercase single letter or any comparable type
-	COMMA                         // ,
-	IN                            // ∈
-	UNION                         // ∪
-	INTERSECT                     // ∩
-	SYMDIFF                       // ⊕
-	ADD                           // +
-	SUBSTRACT                     // -
+	LBRACE     SetTokenType = iota // {
+	RBRACE                         // }
+	NAME                           // identifier starting with an uppercase letter
+	ASSIGN                         // =
+	ELEMENT                        // any other identifier or a number
+	COMMA                          // ,
+	IN                             // ∈
+	UNION                          // ∪
+	INTERSECT                      // ∩
+	SYMDIFF                        // ⊕
+	ADD                            // + (union)
+	SUBSTRACT                      // - \ (difference)
+	LGROUP                         // (
+	RGROUP                         // )
+	SEPARATOR                      // ; or line break between statements
+	COMPLEMENT                     // ' (postfix) ¬ ! (prefix)
 )
 
 func (t SetTokenType) String() string {
@@ -44,7 +51,17 @@ func (t SetTokenType) String() string {
 	case ADD:
 		return "+"
 	case SUBSTRACT:
-		return "-"
+		return "\\"
+	case LGROUP:
+		return "("
+	case RGROUP:
+		return ")"
+	case SEPARATOR:
+		return ";"
+	case COMPLEMENT:
+		return "'"
+	case EOF:
+		return "EOF"
 	default:
 		return "UNKNOWN"
 	}
@@ -59,22 +76,28 @@ func NewSetLexer(input string) *SetLexer {
 	return &SetLexer{input: []rune(input), pos: 0}
 }
 
+// Lex tokenises the whole input. Like BooleanLexer.Lex it does not stop at
+// the first bad rune; every one of them is reported in the returned error.
 func (l *SetLexer) Lex() ([]Token[SetTokenType], error) {
 	var tokens []Token[SetTokenType]
+	var errs []error
 
 	for l.pos < len(l.input) {
-		if unicode.IsSpace(l.input[l.pos]) {
+		if l.input[l.pos] != '\n' && unicode.IsSpace(l.input[l.pos]) {
 			l.pos++
 			continue
 		}
+		startPos := l.pos
 		token, err := l.NextToken()
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
+			l.pos = startPos + 1
+			continue
 		}
 		tokens = append(tokens, token)
 	}
 
-	return tokens, nil
+	return tokens, errors.Join(errs...)
 }
 
 func (l *SetLexer) NextToken() (Token[SetTokenType], error) {
@@ -109,24 +132,41 @@ func (l *SetLexer) NextToken() (Token[SetTokenType], error) {
 	case '+':
 		l.pos++
 		return Token[SetTokenType]{Type: ADD, Value: "+", Pos: startPos}, nil
-	case '-':
+	case '-', '\\':
+		l.pos++
+		return Token[SetTokenType]{Type: SUBSTRACT, Value: string(r), Pos: startPos}, nil
+	case '(':
+		l.pos++
+		return Token[SetTokenType]{Type: LGROUP, Value: "(", Pos: startPos}, nil
+	case ')':
+		l.pos++
+		return Token[SetTokenType]{Type: RGROUP, Value: ")", Pos: startPos}, nil
+	case '\'', '¬', '!':
 		l.pos++
-		return Token[SetTokenType]{Type: SUBSTRACT, Value: "-", Pos: startPos}, nil
+		return Token[SetTokenType]{Type: COMPLEMENT, Value: string(r), Pos: startPos}, nil
+	case ';', '\n':
+		l.pos++
+		return Token[SetTokenType]{Type: SEPARATOR, Value: string(r), Pos: startPos}, nil
 	default:
-		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+		if isIdentifierStart(r) || unicode.IsNumber(r) {
 			return l.lexIdentifier()
 		}
-		return Token[SetTokenType]{}, UnexpectedRuneError{pos: l.pos, r: r}
+		return Token[SetTokenType]{}, UnexpectedRuneError{Rune: r, Pos: l.pos}
 	}
 }
 
+// lexIdentifier reads a set name or an element. Both are made of letters,
+// digits and underscores; names start with an uppercase letter, elements with
+// anything else, so that 12, x and x_1 are elements and A, Evens are names.
 func (l *SetLexer) lexIdentifier() (Token[SetTokenType], error) {
-	r := l.input[l.pos]
 	startPos := l.pos
-	l.pos++
+	for l.pos < len(l.input) && (isIdentifierPart(l.input[l.pos]) || unicode.IsNumber(l.input[l.pos])) {
+		l.pos++
+	}
+	value := string(l.input[startPos:l.pos])
 
-	if unicode.IsLower(r) || unicode.IsNumber(r) {
-		return Token[SetTokenType]{Type: ELEMENT, Value: string(r), Pos: startPos}, nil
+	if unicode.IsUpper(l.input[startPos]) {
+		return Token[SetTokenType]{Type: NAME, Value: value, Pos: startPos}, nil
 	}
-	return Token[SetTokenType]{Type: NAME, Value: string(r), Pos: startPos}, nil
+	return Token[SetTokenType]{Type: ELEMENT, Value: value, Pos: startPos}, nil
 }
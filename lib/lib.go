@@ -1,35 +1,65 @@
 package lib
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/fol"
 	"logicka/lib/lexer"
 	"logicka/lib/parser"
+	"logicka/lib/qbf"
+	"logicka/lib/sets"
 	"logicka/lib/simplification/rules/advanced"
 	"logicka/lib/simplification/rules/basic"
 	"logicka/lib/simplification/rules/chain"
+	"logicka/lib/utils"
 	"logicka/lib/visitor"
-	"regexp"
-	"slices"
-	"strings"
+	"maps"
+	"sync/atomic"
 )
 
 type Logicka struct {
+	ctx    context.Context // the Wails context, set by Startup
+	stream atomic.Int64    // the ID of the truth table being streamed
 }
 
-func (l *Logicka) CalculateTruthTable(expr string, values map[string]bool) ([]visitor.TruthTableEntry, error) {
-	lex := lexer.NewBooleanLexer(expr)
-	tokens, err := lex.Lex()
-	if err != nil {
-		return nil, err
+// Startup keeps the context of the application, which streaming methods
+// need to emit events. It is the OnStartup hook of the application.
+func (l *Logicka) Startup(ctx context.Context) {
+	l.ctx = ctx
+}
+
+// ErrorResponse is what the frontend receives instead of a plain message when
+// a Logicka method fails because the formula could not be parsed.
+type ErrorResponse struct {
+	Message     string
+	Diagnostics parser.ErrorList
+}
+
+// FormatError formats errors returned by Logicka methods for the frontend.
+// Parse errors are sent as structured diagnostics, any other error as its message.
+func FormatError(err error) any {
+	var diagnostics parser.ErrorList
+	if errors.As(err, &diagnostics) {
+		return ErrorResponse{Message: err.Error(), Diagnostics: diagnostics}
 	}
+	return err.Error()
+}
 
-	p := &parser.Parser{Tokens: tokens}
-	ast, err := p.ParseExpression()
+// CalculateTruthTable returns the truth table of expr after simplifying it.
+// Free variables are fixed by values or enumerated. The variables are listed
+// in order, then alphabetically, and the rows follow in binary counting
+// order, so equal inputs always give the same table. Tables of more than
+// visitor.MaxSolveVariables variables are read with GetTruthTablePage or
+// StreamTruthTable instead.
+func (l *Logicka) CalculateTruthTable(expr string, values map[string]bool, order []string) ([]visitor.TruthTableEntry, error) {
+	ast, err := parser.Parse(expr)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx := &visitor.EvaluationContext{Variables: values}
+	ctx := &visitor.EvaluationContext{Variables: values, Order: order}
 	solver := visitor.NewBooleanSolver(ctx)
 	simplifier := visitor.NewSimplifier()
 	simplifier.AddRuleSet(basic.CreateBasicRuleSet())
@@ -47,22 +77,184 @@ func (l *Logicka) CalculateTruthTable(expr string, values map[string]bool) ([]vi
 		return nil, fmt.Errorf("solving error: %w", err)
 	}
 
-	for _, entry := range table {
-		slices.SortFunc(entry.Variables, sortVariables)
+	return table, nil
+}
+
+// CalculateTruthTableSteps returns the step-by-step truth table of expr, in
+// which every row also holds the value of each subformula. The formula is not
+// simplified first, so that the columns are the subformulas as written.
+func (l *Logicka) CalculateTruthTableSteps(expr string, values map[string]bool, order []string) ([]visitor.TruthTableEntry, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
 	}
 
+	ctx := &visitor.EvaluationContext{Variables: values, Order: order}
+	table, err := visitor.NewBooleanSolver(ctx).SolveSteps(node)
+	if err != nil {
+		return nil, fmt.Errorf("solving error: %w", err)
+	}
 	return table, nil
 }
 
-func (l *Logicka) SimplifyExpression(expr string) (string, error) {
-	lex := lexer.NewBooleanLexer(expr)
-	tokens, err := lex.Lex()
+// GetTruthTablePage returns at most limit rows of the truth table of expr
+// that pass filter, skipping the first offset of them. Free variables are
+// fixed by values or enumerated, and order orders the variables as in
+// CalculateTruthTable. Unlike CalculateTruthTable the formula is not
+// simplified first, so the page is computed from the row numbers alone and
+// tables of many variables can be browsed.
+func (l *Logicka) GetTruthTablePage(expr string, values map[string]bool, order []string, offset, limit int, filter visitor.RowFilter) (*visitor.TruthTablePage, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &visitor.EvaluationContext{Variables: values, Order: order}
+	return visitor.NewBooleanSolver(ctx).SolvePage(node, offset, limit, filter)
+}
+
+// CheckModel evaluates a first-order formula in a finite structure. sets is
+// a program in the set syntax of EvaluateSets defining, over the domain of
+// the structure, the sets bounded quantifiers such as ∀x ∈ S range over. Free
+// propositional variables are fixed by values or enumerated as in
+// CalculateTruthTable; every row explains its quantifiers with witnesses and
+// counterexamples. The formula is not simplified first, so that the witnesses
+// refer to the quantifiers as written.
+func (l *Logicka) CheckModel(expr string, structure visitor.Structure, sets string, values map[string]bool) ([]visitor.TruthTableEntry, error) {
+	node, err := parser.Parse(expr)
 	if err != nil {
-		return "", fmt.Errorf("lexing error: %w", err)
+		return nil, err
 	}
 
-	p := &parser.Parser{Tokens: tokens}
-	ast, err := p.ParseExpression()
+	statements, err := parser.ParseSets(sets)
+	if err != nil {
+		return nil, err
+	}
+	results, err := visitor.NewSetEvaluator(utils.NewSetFrom(structure.Domain...)).Evaluate(statements)
+	if err != nil {
+		return nil, err
+	}
+	structure.Sets = maps.Clone(structure.Sets)
+	if structure.Sets == nil {
+		structure.Sets = make(map[string][]string)
+	}
+	for _, result := range results {
+		if result.Name != "" {
+			structure.Sets[result.Name] = result.Elements
+		}
+	}
+
+	signature, err := ast.SignatureOf(node)
+	if err != nil {
+		return nil, err
+	}
+	if err := structure.Check(signature); err != nil {
+		return nil, err
+	}
+
+	ctx := &visitor.EvaluationContext{Variables: values, Structure: &structure}
+	table, err := visitor.NewBooleanSolver(ctx).Solve(node)
+	if err != nil {
+		return nil, fmt.Errorf("solving error: %w", err)
+	}
+
+	return table, nil
+}
+
+// FindCounterModel searches for the smallest interpretation, with at most
+// maxDomain elements, in which a first-order formula is false; zero means
+// fol.DefaultMaxDomain.
+func (l *Logicka) FindCounterModel(expr string, maxDomain int) (*fol.SearchResult, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return fol.FindCounterModel(node, maxDomain)
+}
+
+// AnalyzeVariables returns the free and bound variables of a formula and the
+// variables that occur both free and bound.
+func (l *Logicka) AnalyzeVariables(expr string) (*ast.VariableAnalysis, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := ast.AnalyzeVariables(node)
+	return &analysis, nil
+}
+
+// Substitute replaces the free occurrences of variable in a formula by a term,
+// renaming bound variables where the term would otherwise be captured.
+func (l *Logicka) Substitute(expr, variable, term string) (string, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return "", err
+	}
+	replacement, err := parser.ParseTerm(term)
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Substitute(node, variable, replacement).String(), nil
+}
+
+// ProveFormula tries to prove a first-order formula by resolution, deriving
+// at most maxSteps clauses; zero means fol.DefaultResolutionSteps.
+func (l *Logicka) ProveFormula(expr string, maxSteps int) (*fol.Proof, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return fol.Prove(node, maxSteps)
+}
+
+// PrenexNormalForm moves the quantifiers of a formula to its front and
+// returns the result with the derivation steps.
+func (l *Logicka) PrenexNormalForm(expr string) (*visitor.Derivation, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	prenex, steps, err := visitor.NewPrenexConverter().Convert(node)
+	if err != nil {
+		return nil, err
+	}
+	return &visitor.Derivation{Result: prenex.String(), Steps: steps}, nil
+}
+
+// Skolemize brings a formula into prenex normal form and replaces its
+// existential quantifiers by Skolem functions and constants.
+func (l *Logicka) Skolemize(expr string) (*visitor.Derivation, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	skolemized, steps, err := visitor.NewSkolemizer().Skolemize(node)
+	if err != nil {
+		return nil, err
+	}
+	return &visitor.Derivation{Result: skolemized.String(), Steps: steps}, nil
+}
+
+// SolveQBF evaluates a quantified boolean formula, whose quantifiers range
+// over truth values, and returns a winning strategy for the player who wins.
+// Its free variables take the given values.
+func (l *Logicka) SolveQBF(expr string, values map[string]bool) (*qbf.Result, error) {
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return qbf.Solve(node, values)
+}
+
+func (l *Logicka) SimplifyExpression(expr string) (string, error) {
+	ast, err := parser.Parse(expr)
 	if err != nil {
 		return "", err
 	}
@@ -76,32 +268,73 @@ func (l *Logicka) SimplifyExpression(expr string) (string, error) {
 	return simplified.String(), nil
 }
 
-func sortVariables(a, b visitor.TruthTableVariable) int {
-	return strings.Compare(a.Name, b.Name)
+// EvaluateSets evaluates a program of set definitions, expressions and
+// membership tests, one statement per line, and returns the value of every
+// statement. Complements are taken relative to universe; when it is empty
+// they are rejected.
+func (l *Logicka) EvaluateSets(input string, universe []string) ([]visitor.SetResult, error) {
+	statements, err := parser.ParseSets(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var u *utils.Set[string]
+	if len(universe) > 0 {
+		u = utils.NewSetFrom(universe...)
+	}
+	return visitor.NewSetEvaluator(u).Evaluate(statements)
 }
 
+// ProveSetIdentity decides whether an identity between set expressions, such
+// as A \ (B ∪ C) = (A \ B) ∩ (A \ C), holds for all sets.
+func (l *Logicka) ProveSetIdentity(identity string) (*sets.IdentityResult, error) {
+	left, right, err := parser.ParseSetIdentity(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return sets.ProveIdentity(left, right)
+}
+
+// VennRegions returns the regions of the Venn diagram of at most four sets
+// together with whether the set expression covers each of them. names fixes
+// the sets of the diagram and their order; it may be empty.
+func (l *Logicka) VennRegions(expr string, names []string) (*sets.Diagram, error) {
+	node, err := parser.ParseSet(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return sets.Venn(node, names)
+}
+
+// ExtractVariables returns the distinct propositional variables of expr in
+// order of first appearance. Arguments of predicates are terms, not
+// propositional variables, and are left out. While expr does not parse the
+// variables are read off its tokens; expressions that cannot be tokenised
+// yield no variables.
 func (l *Logicka) ExtractVariables(expr string) []string {
-	re := regexp.MustCompile(`\b[a-z]+\b`)
-	words := re.FindAllString(expr, -1)
+	if node, err := parser.Parse(expr); err == nil {
+		return visitor.PropositionalVariables(node)
+	}
 
-	keywords := map[string]struct{}{
-		"true": {}, "false": {}, "nil": {},
-		"and": {}, "or": {}, "not": {},
-		"if": {}, "else": {}, "for": {},
+	tokens, err := lexer.NewBooleanLexer(expr).Lex()
+	if err != nil {
+		return nil
 	}
 
 	seen := make(map[string]struct{})
 	var variables []string
 
-	for _, word := range words {
-		if _, ok := keywords[word]; ok {
+	for _, token := range tokens {
+		if token.Type != lexer.VAR {
 			continue
 		}
-		if _, ok := seen[word]; ok {
+		if _, ok := seen[token.Value]; ok {
 			continue
 		}
-		seen[word] = struct{}{}
-		variables = append(variables, word)
+		seen[token.Value] = struct{}{}
+		variables = append(variables, token.Value)
 	}
 
 	return variables
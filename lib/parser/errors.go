@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"slices"
+	"strings"
+)
+
+// ParseError describes a single lexical or syntax error in a formula.
+type ParseError struct {
+	Message  string
+	Span     lexer.Span // rune offsets into the formula
+	ByteSpan lexer.Span // byte offsets into the formula, filled in by Parse
+	Expected []string   // tokens that would have been accepted at Span
+	Found    string     // text of the offending token, empty at end of input
+	Rendered string     // caret-underlined excerpt of the formula, filled in by Parse
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Span.Start)
+}
+
+// Render returns the message followed by the line of input the error is on,
+// with the offending span underlined by carets:
+//
+//	expected ), found end of input
+//	a & (b ∨ c
+//	          ^
+func (e *ParseError) Render(input string) string {
+	runes := []rune(input)
+	start := min(e.Span.Start, len(runes))
+
+	lineStart := start
+	for lineStart > 0 && runes[lineStart-1] != '\n' {
+		lineStart--
+	}
+	lineEnd := start
+	for lineEnd < len(runes) && runes[lineEnd] != '\n' {
+		lineEnd++
+	}
+
+	var b strings.Builder
+	b.WriteString(e.Message)
+	b.WriteByte('\n')
+	b.WriteString(string(runes[lineStart:lineEnd]))
+	b.WriteByte('\n')
+	for _, r := range runes[lineStart:start] {
+		// Keep tabs so the carets line up with the source line.
+		if r == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	width := min(e.Span.End, lineEnd) - start
+	b.WriteString(strings.Repeat("^", max(width, 1)))
+
+	return b.String()
+}
+
+// ErrorList collects every error found in a formula, ordered by position.
+type ErrorList []*ParseError
+
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, err := range l {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Render renders every error in the list against input, separated by blank lines.
+func (l ErrorList) Render(input string) string {
+	rendered := make([]string, len(l))
+	for i, err := range l {
+		rendered[i] = err.Render(input)
+	}
+	return strings.Join(rendered, "\n\n")
+}
+
+func (l ErrorList) sort() {
+	slices.SortStableFunc(l, func(a, b *ParseError) int {
+		return a.Span.Start - b.Span.Start
+	})
+}
+
+// locate sorts the list and fills in the fields that need the original input.
+func (l ErrorList) locate(input string) ErrorList {
+	l.sort()
+	for _, err := range l {
+		err.ByteSpan = err.Span.ByteSpan(input)
+		err.Rendered = err.Render(input)
+	}
+	return l
+}
+
+func newUnexpectedTokenError(token lexer.Token[lexer.BooleanTokenType], expected, operators []lexer.BooleanTokenType) *ParseError {
+	names := make([]string, len(expected))
+	for i, tokenType := range expected {
+		names[i] = tokenType.String()
+	}
+
+	found := "end of input"
+	if token.Type != lexer.EOF {
+		found = fmt.Sprintf("'%s'", token.Value)
+	}
+
+	return &ParseError{
+		Message:  fmt.Sprintf("expected %s, found %s", joinAlternatives(summarize(expected, operators)), found),
+		Span:     token.Span(),
+		Expected: names,
+		Found:    token.Value,
+	}
+}
+
+// newLexErrors converts the error returned by a lexer into parse errors.
+func newLexErrors(err error) ErrorList {
+	var list ErrorList
+	for _, err := range unwrapAll(err) {
+		var runeErr lexer.UnexpectedRuneError
+		if errors.As(err, &runeErr) {
+			list = append(list, &ParseError{
+				Message: fmt.Sprintf("unexpected character '%c'", runeErr.Rune),
+				Span:    lexer.Span{Start: runeErr.Pos, End: runeErr.Pos + 1},
+				Found:   string(runeErr.Rune),
+			})
+			continue
+		}
+		list = append(list, &ParseError{Message: err.Error()})
+	}
+	return list
+}
+
+// newArityErrors converts the error returned by ast.SignatureOf into parse errors.
+func newArityErrors(err error) ErrorList {
+	var list ErrorList
+	for _, err := range unwrapAll(err) {
+		var arityErr ast.ArityError
+		if errors.As(err, &arityErr) {
+			list = append(list, &ParseError{
+				Message: fmt.Sprintf("%s %s takes %s where first used at position %d, found %d",
+					arityErr.Kind, arityErr.Name, arguments(arityErr.Expected), arityErr.First.Start, arityErr.Found),
+				Span: arityErr.Span,
+			})
+			continue
+		}
+		list = append(list, &ParseError{Message: err.Error()})
+	}
+	return list
+}
+
+func arguments(n int) string {
+	if n == 1 {
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d arguments", n)
+}
+
+func unwrapAll(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// summarize names the expected tokens for a message, collapsing the full sets
+// of operand starts and binary operators into "operand" and "operator".
+func summarize(expected, operators []lexer.BooleanTokenType) []string {
+	var names []string
+	hasAll := func(types []lexer.BooleanTokenType) bool {
+		return !slices.ContainsFunc(types, func(t lexer.BooleanTokenType) bool {
+			return !slices.Contains(expected, t)
+		})
+	}
+	allOperands, allOperators := hasAll(operandStarts), hasAll(operators)
+
+	for _, tokenType := range expected {
+		switch {
+		case allOperands && slices.Contains(operandStarts, tokenType):
+			if !slices.Contains(names, "operand") {
+				names = append(names, "operand")
+			}
+		case allOperators && slices.Contains(operators, tokenType):
+			if !slices.Contains(names, "operator") {
+				names = append(names, "operator")
+			}
+		case tokenType == lexer.EOF:
+			names = append(names, "end of input")
+		default:
+			names = append(names, tokenType.String())
+		}
+	}
+	return names
+}
+
+func joinAlternatives(names []string) string {
+	switch len(names) {
+	case 0:
+		return "nothing"
+	case 1:
+		return names[0]
+	default:
+		return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+	}
+}
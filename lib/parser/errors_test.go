@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"errors"
+	"logicka/lib/lexer"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	type located struct {
+		message string
+		span    lexer.Span
+	}
+	tests := []struct {
+		input string
+		want  []located
+	}{
+		{"", []located{{"expected operand, found end of input", lexer.Span{Start: 0, End: 0}}}},
+		{"a ∧", []located{{"expected operand, found end of input", lexer.Span{Start: 3, End: 3}}}},
+		{"(a ∨ b", []located{{"expected ) or operator, found end of input", lexer.Span{Start: 6, End: 6}}}},
+		{"a ∧ ∧ b", []located{{"expected operand, found '∧'", lexer.Span{Start: 4, End: 5}}}},
+		{"a $ b", []located{{"unexpected character '$'", lexer.Span{Start: 2, End: 3}}}},
+		{"a b", []located{{"expected operator or end of input, found 'b'", lexer.Span{Start: 2, End: 3}}}},
+		{"a ∧ b)", []located{{"expected operator or end of input, found ')'", lexer.Span{Start: 5, End: 6}}}},
+		{"∀ P(x)", []located{{"expected VARIABLE, found 'P'", lexer.Span{Start: 2, End: 3}}}},
+		{"P(x,", []located{{"expected VARIABLE, found end of input", lexer.Span{Start: 4, End: 4}}}},
+		{"∀x P(x) ∧ P(x, y)", []located{{"predicate P takes 1 argument where first used at position 3, found 2", lexer.Span{Start: 10, End: 17}}}},
+		// Parsing goes on after an error, and the errors come in order of
+		// position whether the lexer or the parser found them.
+		{"a ∧ (b ∨) ∧ $", []located{
+			{"expected operand, found ')'", lexer.Span{Start: 8, End: 9}},
+			{"unexpected character '$'", lexer.Span{Start: 12, End: 13}},
+		}},
+		{")a", []located{
+			{"expected operand, found ')'", lexer.Span{Start: 0, End: 1}},
+			{"expected operator or end of input, found 'a'", lexer.Span{Start: 1, End: 2}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			var errs ErrorList
+			if !errors.As(err, &errs) {
+				t.Fatalf("Parse(%q) = %v, want an ErrorList", tt.input, err)
+			}
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Parse(%q) = %d errors (%v), want %d", tt.input, len(errs), errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if errs[i].Message != want.message || errs[i].Span != want.span {
+					t.Errorf("error %d = %q at %v, want %q at %v", i, errs[i].Message, errs[i].Span, want.message, want.span)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrorByteSpan(t *testing.T) {
+	_, err := Parse("a ∧ ∧ b")
+	var errs ErrorList
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("Parse = %v, want one error", err)
+	}
+	// ∧ takes three bytes in UTF-8.
+	if want := (lexer.Span{Start: 6, End: 9}); errs[0].ByteSpan != want {
+		t.Errorf("ByteSpan = %v, want %v", errs[0].ByteSpan, want)
+	}
+}
+
+func TestParseErrorRender(t *testing.T) {
+	_, err := Parse("a ∧ (b ∨ c")
+	var errs ErrorList
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("Parse = %v, want one error", err)
+	}
+	want := "expected ) or operator, found end of input\na ∧ (b ∨ c\n          ^"
+	if errs[0].Rendered != want {
+		t.Errorf("Rendered =\n%s\nwant\n%s", errs[0].Rendered, want)
+	}
+}
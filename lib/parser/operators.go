@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"logicka/lib/lexer"
+	"maps"
+	"slices"
+)
+
+// Associativity decides how a run of operators with the same binding power groups.
+type Associativity int
+
+const (
+	LeftAssoc  Associativity = iota // a op b op c = (a op b) op c
+	RightAssoc                      // a op b op c = a op (b op c)
+)
+
+// Operator describes how a binary operator takes part in parsing.
+type Operator struct {
+	// BindingPower orders operators by precedence: the higher it is, the
+	// tighter the operator binds. It must be positive.
+	BindingPower  int
+	Associativity Associativity
+}
+
+// OperatorTable maps binary operator tokens to their binding power and
+// associativity. Tokens missing from the table are not accepted as binary
+// operators. Negation and quantifiers are prefix operators and always bind
+// tighter than any binary operator.
+type OperatorTable map[lexer.BooleanTokenType]Operator
+
+// DefaultOperators returns the textbook precedence ladder
+//
+//	~ ⊕  <  → ←  <  ∨ ↓  <  ∧ ↑
+//
+// with implication right-associative, so that a → b → c means a → (b → c),
+// and converse implication left-associative, so that a ← b ← c means
+// (a ← b) ← c. As they group in opposite directions, → and ← cannot be
+// mixed without parentheses: a → b ← c is an error.
+// The returned table is a fresh copy and may be changed to describe another dialect.
+func DefaultOperators() OperatorTable {
+	return OperatorTable{
+		lexer.EQUIV: {BindingPower: 10, Associativity: LeftAssoc},
+		lexer.XOR:   {BindingPower: 10, Associativity: LeftAssoc},
+		lexer.IMPL:  {BindingPower: 20, Associativity: RightAssoc},
+		lexer.CONV:  {BindingPower: 20, Associativity: LeftAssoc},
+		lexer.DISJ:  {BindingPower: 30, Associativity: LeftAssoc},
+		lexer.NOR:   {BindingPower: 30, Associativity: LeftAssoc},
+		lexer.CONJ:  {BindingPower: 40, Associativity: LeftAssoc},
+		lexer.NAND:  {BindingPower: 40, Associativity: LeftAssoc},
+	}
+}
+
+// Tokens returns the operators in the table in a stable order, loosest first.
+func (t OperatorTable) Tokens() []lexer.BooleanTokenType {
+	tokens := slices.Collect(maps.Keys(t))
+	slices.SortFunc(tokens, func(a, b lexer.BooleanTokenType) int {
+		if t[a].BindingPower != t[b].BindingPower {
+			return t[a].BindingPower - t[b].BindingPower
+		}
+		return int(a) - int(b)
+	})
+	return tokens
+}
+
+// rightPower is the minimum binding power an operator in the right operand
+// needs to be pulled into it.
+func (o Operator) rightPower() int {
+	if o.Associativity == RightAssoc {
+		return o.BindingPower - 1
+	}
+	return o.BindingPower
+}
@@ -4,237 +4,383 @@ import (
 	"fmt"
 	"logicka/lib/ast"
 	"logicka/lib/lexer"
+	"slices"
 )
 
+// operandStarts lists every token that can begin an operand.
+var operandStarts = []lexer.BooleanTokenType{
+	lexer.VAR, lexer.PRED, lexer.LIT, lexer.NEG,
+	lexer.FORALL, lexer.EXISTS, lexer.LPAREN,
+}
+
+// Parser is a Pratt parser for boolean and predicate formulas. Binary
+// operators are read from Operators, so precedence and associativity can be
+// changed per dialect; a nil table means DefaultOperators.
 type Parser struct {
-	Tokens []lexer.Token[lexer.BooleanTokenType]
-	pos    int
+	Tokens    []lexer.Token[lexer.BooleanTokenType]
+	Operators OperatorTable
+	pos       int
+
+	tokens       []lexer.Token[lexer.BooleanTokenType] // Tokens without ILLEGAL ones
+	afterIllegal map[int]bool                          // indices in tokens that follow an ILLEGAL token
+	errors       ErrorList
+	lastEnd      int                      // end of the last consumed token
+	operators    OperatorTable            // Operators or the default table
+	binary       []lexer.BooleanTokenType // tokens of operators
 }
 
-func (p *Parser) current() lexer.Token[lexer.BooleanTokenType] {
-	if p.pos >= len(p.Tokens) {
-		return lexer.Token[lexer.BooleanTokenType]{Type: lexer.EOF, Value: ""}
-	}
-	return p.Tokens[p.pos]
+// Parse lexes and parses input with the default operator table. Lexical and
+// syntax errors do not stop it: every error in the formula is reported at once
+// as an ErrorList.
+func Parse(input string) (ast.ASTNode, error) {
+	return ParseWithOperators(input, nil)
 }
 
-func (p *Parser) peek() lexer.Token[lexer.BooleanTokenType] {
-	if p.pos+1 >= len(p.Tokens) {
-		return lexer.Token[lexer.BooleanTokenType]{Type: lexer.EOF, Value: ""}
+// ParseWithOperators is Parse with a custom operator table.
+func ParseWithOperators(input string, operators OperatorTable) (ast.ASTNode, error) {
+	tokens, lexErr := lexer.NewBooleanLexer(input).Lex()
+
+	p := &Parser{Tokens: tokens, Operators: operators}
+	node, _ := p.ParseExpression()
+
+	errs := append(newLexErrors(lexErr), p.errors...)
+	if len(errs) == 0 {
+		_, err := ast.SignatureOf(node)
+		if err == nil {
+			return node, nil
+		}
+		errs = newArityErrors(err)
 	}
-	return p.Tokens[p.pos+1]
+
+	return nil, errs.locate(input)
 }
 
-func (p *Parser) advance() {
-	if p.pos < len(p.Tokens) {
-		p.pos++
+// ParseTerm lexes and parses a single term, such as x or f(x, g(y)).
+func ParseTerm(input string) (ast.ASTNode, error) {
+	tokens, lexErr := lexer.NewBooleanLexer(input).Lex()
+
+	p := &Parser{Tokens: tokens}
+	p.prepare()
+	term := p.parseTerm()
+	if p.current().Type != lexer.EOF && !p.hasErrorAt(p.current().Span()) {
+		p.unexpected(lexer.EOF)
 	}
-}
 
-func (p *Parser) expect(tokenType lexer.BooleanTokenType) error {
-	if p.current().Type != tokenType {
-		return fmt.Errorf("expected %s, got %s", tokenType.String(), p.current().Type.String())
+	errs := append(newLexErrors(lexErr), p.errors...)
+	if len(errs) == 0 {
+		_, err := ast.SignatureOf(term)
+		if err == nil {
+			return term, nil
+		}
+		errs = newArityErrors(err)
 	}
-	p.advance()
-	return nil
+
+	return nil, errs.locate(input)
 }
 
-// <expr> ::= <equal>
-func (p *Parser) ParseExpression() (ast.ASTNode, error) {
-	expr, err := p.parseEqual()
-	if err != nil {
-		return nil, err
+// prepare drops ILLEGAL tokens, which the lexer has already reported, and
+// remembers where they were so that no second error is reported right after them.
+func (p *Parser) prepare() {
+	p.pos = 0
+	p.lastEnd = 0
+	p.errors = nil
+	p.tokens = make([]lexer.Token[lexer.BooleanTokenType], 0, len(p.Tokens))
+	p.afterIllegal = make(map[int]bool)
+
+	p.operators = p.Operators
+	if p.operators == nil {
+		p.operators = DefaultOperators()
 	}
+	p.binary = p.operators.Tokens()
 
-	if p.current().Type != lexer.EOF {
-		return nil, fmt.Errorf("unexpected Token[lexer.BooleanTokenType] %s at pos %d, expected end of expression", p.current().Type.String(), p.current().Pos)
+	for _, token := range p.Tokens {
+		if token.Type == lexer.ILLEGAL {
+			p.afterIllegal[len(p.tokens)] = true
+			continue
+		}
+		p.tokens = append(p.tokens, token)
 	}
+}
 
-	return expr, nil
+func (p *Parser) current() lexer.Token[lexer.BooleanTokenType] {
+	if p.pos >= len(p.tokens) {
+		return p.eof()
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *Parser) peek() lexer.Token[lexer.BooleanTokenType] {
+	if p.pos+1 >= len(p.tokens) {
+		return p.eof()
+	}
+	return p.tokens[p.pos+1]
 }
 
-// <equal> ::= <impl> ("~" <impl>)*
-func (p *Parser) parseEqual() (ast.ASTNode, error) {
-	left, err := p.parseImpl()
-	if err != nil {
-		return nil, err
+func (p *Parser) eof() lexer.Token[lexer.BooleanTokenType] {
+	end := 0
+	if len(p.Tokens) > 0 {
+		end = p.Tokens[len(p.Tokens)-1].Span().End
 	}
+	return lexer.Token[lexer.BooleanTokenType]{Type: lexer.EOF, Value: "", Pos: end}
+}
 
-	for p.current().Type == lexer.EQUIV {
-		p.advance() // consume "~"
-		right, err := p.parseImpl()
-		if err != nil {
-			return nil, err
-		}
-		left = &ast.BinaryNode{Operator: lexer.EQUIV, Left: left, Right: right}
+func (p *Parser) advance() {
+	if p.pos < len(p.tokens) {
+		p.lastEnd = p.tokens[p.pos].Span().End
+		p.pos++
 	}
+}
+
+// spanFrom returns the span from start up to the end of the last consumed token.
+func (p *Parser) spanFrom(start int) lexer.Span {
+	return lexer.Span{Start: start, End: max(p.lastEnd, start)}
+}
 
-	return left, nil
+// at sets the source span of a freshly built node.
+func at[T ast.ASTNode](node T, span lexer.Span) T {
+	node.SetSpan(span)
+	return node
 }
 
-// <impl> ::= <or> ("->" <or>)*
-func (p *Parser) parseImpl() (ast.ASTNode, error) {
-	left, err := p.parseOr()
-	if err != nil {
-		return nil, err
+// expect consumes a token of the given type. When the current token does not
+// match, the error is recorded and nothing is consumed, as if the missing token
+// had been inserted.
+func (p *Parser) expect(tokenType lexer.BooleanTokenType, alternatives ...lexer.BooleanTokenType) bool {
+	if p.current().Type != tokenType {
+		p.unexpected(append([]lexer.BooleanTokenType{tokenType}, alternatives...)...)
+		return false
 	}
+	p.advance()
+	return true
+}
 
-	for p.current().Type == lexer.IMPL {
-		p.advance() // consume "->"
-		right, err := p.parseOr()
-		if err != nil {
-			return nil, err
+// unexpected records that the current token is not one of expected.
+func (p *Parser) unexpected(expected ...lexer.BooleanTokenType) {
+	if p.afterIllegal[p.pos] {
+		return
+	}
+	err := newUnexpectedTokenError(p.current(), expected, p.binary)
+	for _, recorded := range p.errors {
+		if recorded.Span == err.Span && recorded.Message == err.Message {
+			return
 		}
-		left = &ast.BinaryNode{Operator: lexer.IMPL, Left: left, Right: right}
 	}
+	p.errors = append(p.errors, err)
+}
 
-	return left, nil
+func (p *Parser) hasErrorAt(span lexer.Span) bool {
+	return slices.ContainsFunc(p.errors, func(err *ParseError) bool {
+		return err.Span == span
+	})
 }
 
-// <or> ::= <and> ("\\/" <and>)*
-func (p *Parser) parseOr() (ast.ASTNode, error) {
-	left, err := p.parseAnd()
-	if err != nil {
-		return nil, err
-	}
+// missing stands in for an operand that could not be parsed. It never leaves
+// the parser because any recorded error discards the tree.
+func (p *Parser) missing() ast.ASTNode {
+	return &ast.LiteralNode{Value: false}
+}
 
-	for p.current().Type == lexer.DISJ {
-		p.advance() // consume "\\/"
-		right, err := p.parseAnd()
-		if err != nil {
-			return nil, err
+// <expr> ::= <unary> (<binary-op> <unary>)*, grouped by the operator table
+func (p *Parser) ParseExpression() (ast.ASTNode, error) {
+	p.prepare()
+	expr := p.parseExpr(0)
+
+	// Report every stray token instead of stopping at the first one. After a
+	// stray token parsing resumes as if an operand had been read, so that
+	// errors further to the right are found as well.
+	for p.current().Type != lexer.EOF {
+		switch {
+		case slices.Contains(p.binary, p.current().Type):
+			p.advance()
+			p.parseExpr(0)
+		case slices.Contains(operandStarts, p.current().Type):
+			p.unexpected(slices.Concat(p.binary, []lexer.BooleanTokenType{lexer.EOF})...)
+			p.parseExpr(0)
+		default:
+			if !p.hasErrorAt(p.current().Span()) {
+				p.unexpected(slices.Concat(p.binary, []lexer.BooleanTokenType{lexer.EOF})...)
+			}
+			p.advance()
 		}
-		left = &ast.BinaryNode{Operator: lexer.DISJ, Left: left, Right: right}
 	}
 
-	return left, nil
+	if len(p.errors) > 0 {
+		return nil, p.errors
+	}
+	return expr, nil
 }
 
-// <and> ::= <not> ("&" <not>)*
-func (p *Parser) parseAnd() (ast.ASTNode, error) {
-	left, err := p.parseNot()
-	if err != nil {
-		return nil, err
+// parseExpr parses an operand followed by every binary operator that binds
+// tighter than minPower. Right operands are parsed recursively with the
+// operator's own power, which makes equal operators group to the left, or
+// one less for right-associative operators, which makes them group to the right.
+func (p *Parser) parseExpr(minPower int) ast.ASTNode {
+	left := p.parseNot()
+
+	for {
+		token := p.current()
+		operator := token.Type
+		info, ok := p.operators[operator]
+		if !ok || info.BindingPower <= minPower {
+			return left
+		}
+		p.advance() // consume the operator
+		right := p.parseExpr(info.rightPower())
+		p.checkMixed(token, left, right)
+		left = at(&ast.BinaryNode{Operator: operator, Left: left, Right: right}, left.Span().Cover(right.Span()))
 	}
+}
 
-	for p.current().Type == lexer.CONJ {
-		p.advance() // consume "&"
-		right, err := p.parseNot()
-		if err != nil {
-			return nil, err
+// checkMixed reports an operand of the operator token that is an operator of
+// the same binding power but the opposite associativity, as in a → b ← c.
+// Neither grouping of such a run is more natural than the other, so it needs
+// parentheses.
+func (p *Parser) checkMixed(token lexer.Token[lexer.BooleanTokenType], left, right ast.ASTNode) {
+	info := p.operators[token.Type]
+	for _, operand := range []ast.ASTNode{left, right} {
+		inner, ok := operand.(*ast.BinaryNode)
+		if !ok {
+			continue
+		}
+		other, ok := p.operators[inner.Operator]
+		if !ok || other.BindingPower != info.BindingPower || other.Associativity == info.Associativity {
+			continue
 		}
-		left = &ast.BinaryNode{Operator: lexer.CONJ, Left: left, Right: right}
-	}
 
-	return left, nil
+		first, second := inner.Operator, token.Type
+		if operand == right {
+			first, second = second, first
+		}
+		p.errors = append(p.errors, &ParseError{
+			Message: fmt.Sprintf("%s and %s cannot be mixed without parentheses", first, second),
+			Span:    token.Span(),
+			Found:   token.Value,
+		})
+	}
 }
 
-// <not> ::= "-" <pred> | <pred>
-func (p *Parser) parseNot() (ast.ASTNode, error) {
-	for p.current().Type == lexer.NEG {
+// <not> ::= "-" <not> | <quant>
+func (p *Parser) parseNot() ast.ASTNode {
+	if p.current().Type == lexer.NEG {
+		start := p.current().Pos
 		p.advance() // consume "-"
-		expr, err := p.parseNot()
-		if err != nil {
-			return nil, err
-		}
-		return &ast.UnaryNode{Operator: lexer.NEG, Operand: expr}, nil
+		expr := p.parseNot()
+		return at(&ast.UnaryNode{Operator: lexer.NEG, Operand: expr}, p.spanFrom(start))
 	}
-	return p.parsePred()
+	return p.parseQuant()
 }
 
-// <pred> ::= [A-Z] <quant> | [A-Z] "(" <quant> ")" | <quant>
-func (p *Parser) parsePred() (ast.ASTNode, error) {
-	if p.current().Type == lexer.PRED {
-		predName := p.current().Value
+// <quant> ::= ("A" | "E") [a-z] ["∈" [A-Z]] <not> | <atom>
+//
+// The body of a quantifier is parsed like the operand of a negation, so
+// A x P(x) & Q(x) means (A x P(x)) & Q(x); a wider scope needs parentheses.
+// A set name after "∈" bounds the quantifier to the elements of that set.
+func (p *Parser) parseQuant() ast.ASTNode {
+	if p.current().Type == lexer.FORALL || p.current().Type == lexer.EXISTS {
+		start := p.current().Pos
+		quantType := p.current().Type
 		p.advance()
 
-		// Check if this is a predicate with arguments: [A-Z] "(" <quant> ")"
-		if p.current().Type == lexer.LPAREN {
-			p.advance() // consume "("
-
-			var args []string
-			// Parse arguments (variables)
-			for p.current().Type == lexer.VAR {
-				args = append(args, p.current().Value)
-				p.advance()
-			}
+		variable := p.current().Value
+		if !p.expect(lexer.VAR) {
+			variable = ""
+		}
 
-			if err := p.expect(lexer.RPAREN); err != nil {
-				return nil, err
+		var domain ast.ASTNode
+		if p.current().Type == lexer.MEMBER {
+			p.advance() // consume "∈"
+			token := p.current()
+			if p.expect(lexer.PRED) {
+				domain = at(ast.NewSetNameNode(token.Value), token.Span())
+			} else if token.Type == lexer.VAR {
+				p.advance() // a misspelt set name, not the start of the body
 			}
-			return &ast.PredicateNode{Name: predName, Args: nil}, nil
-		} else {
-			// This is a predicate followed by a quantifier: [A-Z] <quant>
-			_, err := p.parseQuant()
-			if err != nil {
-				return nil, err
-			}
-			return &ast.PredicateNode{Name: predName, Args: nil}, nil
 		}
+
+		body := p.parseNot()
+		return at(&ast.QuantifierNode{Type: quantType, Variable: variable, Domain: domain, Body: body}, p.spanFrom(start))
 	}
-	return p.parseQuant()
+	return p.parseAtom()
 }
 
-// <quant> ::= ("A" | "E") <primary> | ("A" | "E") "(" <primary> ")" | <primary>
-func (p *Parser) parseQuant() (ast.ASTNode, error) {
-	if p.current().Type == lexer.FORALL || p.current().Type == lexer.EXISTS {
-		quantType := p.current().Type
+// <atom> ::= [A-Z] [<arguments>] | <primary>
+func (p *Parser) parseAtom() ast.ASTNode {
+	if p.current().Type == lexer.PRED {
+		token := p.current()
 		p.advance()
 
-		// Check for quantifier with parentheses: ("A" | "E") "(" <primary> ")"
+		var args []ast.ASTNode
 		if p.current().Type == lexer.LPAREN {
-			p.advance() // consume "("
-			// Expect a variable
-			if p.current().Type != lexer.VAR {
-				return nil, fmt.Errorf("expected variable after quantifier, got %s", p.current().Type.String())
-			}
-			variable := p.current().Value
-			p.advance()
+			args = p.parseArguments()
+		}
+		return at(&ast.PredicateNode{Name: token.Value, Args: args}, p.spanFrom(token.Pos))
+	}
+	return p.parsePrimary()
+}
 
-			if err := p.expect(lexer.RPAREN); err != nil {
-				return nil, err
-			}
+// <arguments> ::= "(" <term> ("," <term>)* ")"
+func (p *Parser) parseArguments() []ast.ASTNode {
+	p.advance() // consume "("
 
-			return &ast.QuantifierNode{Type: quantType, Variable: variable, Domain: nil}, nil
-		} else {
-			// Simple quantifier: ("A" | "E") <primary>
-			body, err := p.parsePrimary()
-			if err != nil {
-				return nil, err
-			}
-			return &ast.QuantifierNode{Type: quantType, Variable: "", Domain: body}, nil
+	args := []ast.ASTNode{p.parseTerm()}
+	for p.current().Type == lexer.DELIM {
+		p.advance() // consume ","
+		args = append(args, p.parseTerm())
+	}
+
+	// A term that could not be parsed has already been reported here.
+	if p.current().Type == lexer.RPAREN || !p.hasErrorAt(p.current().Span()) {
+		p.expect(lexer.RPAREN, lexer.DELIM)
+	}
+	return args
+}
+
+// <term> ::= [a-z] [<arguments>]
+//
+// A term without arguments is a variable; whether it is bound by a
+// quantifier or names a constant is decided where the formula is evaluated.
+func (p *Parser) parseTerm() ast.ASTNode {
+	token := p.current()
+	if token.Type != lexer.VAR {
+		p.unexpected(lexer.VAR)
+		if token.Type != lexer.DELIM && token.Type != lexer.RPAREN {
+			p.advance()
 		}
+		return p.missing()
 	}
-	return p.parsePrimary()
+	p.advance()
+
+	if p.current().Type == lexer.LPAREN {
+		args := p.parseArguments()
+		return at(&ast.FunctionNode{Name: token.Value, Args: args}, p.spanFrom(token.Pos))
+	}
+	return at(&ast.VariableNode{Name: token.Value}, token.Span())
 }
 
 // <primary> ::= [a-z] | "(" <expr> ")"
-func (p *Parser) parsePrimary() (ast.ASTNode, error) {
-	if p.current().Type == lexer.LIT {
-		literal := p.current().Value
+func (p *Parser) parsePrimary() ast.ASTNode {
+	token := p.current()
+	if token.Type == lexer.LIT {
 		p.advance()
-		if literal == "1" {
-			return &ast.LiteralNode{Value: true}, nil
-		}
-		return &ast.LiteralNode{Value: false}, nil
+		return at(&ast.LiteralNode{Value: lexer.LiteralValue(token.Value)}, token.Span())
 	}
-	if p.current().Type == lexer.VAR {
-		variable := p.current().Value
+	if token.Type == lexer.VAR {
 		p.advance()
-		return &ast.VariableNode{Name: variable}, nil
+		return at(&ast.VariableNode{Name: token.Value}, token.Span())
 	}
 
-	if p.current().Type == lexer.LPAREN {
+	if token.Type == lexer.LPAREN {
 		p.advance() // consume "("
-		expr, err := p.parseEqual()
-		if err != nil {
-			return nil, err
-		}
-		if err := p.expect(lexer.RPAREN); err != nil {
-			return nil, err
-		}
-		return &ast.GroupingNode{Expr: expr}, nil
+		expr := p.parseExpr(0)
+		p.expect(lexer.RPAREN, p.binary...)
+		return at(&ast.GroupingNode{Expr: expr}, p.spanFrom(token.Pos))
 	}
 
-	return nil, fmt.Errorf("expected variable or '(', got %s", p.current().Type.String())
+	p.unexpected(operandStarts...)
+	// Skip the offending token unless it can continue the surrounding
+	// expression, so that parsing resumes at the next operator or ")".
+	if !slices.Contains(p.binary, p.current().Type) && p.current().Type != lexer.RPAREN {
+		p.advance()
+	}
+	return p.missing()
 }
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"logicka/lib/ast"
+	"testing"
+)
+
+// grouped prints node with every binary operation in parentheses, so that
+// tests see how the operators were grouped.
+func grouped(node ast.ASTNode) string {
+	switch n := node.(type) {
+	case *ast.BinaryNode:
+		return "(" + grouped(n.Left) + " " + n.Operator.String() + " " + grouped(n.Right) + ")"
+	case *ast.GroupingNode:
+		return grouped(n.Expr)
+	case *ast.UnaryNode:
+		return n.Operator.String() + grouped(n.Operand)
+	default:
+		return node.String()
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"a", "a"},
+		{"1 ∧ 0", "true ∧ false"},
+		{"!!a", "!!a"},
+		{"a & b -> c", "a ∧ b → c"},
+		{"(a ∨ b) ∧ c", "(a ∨ b) ∧ c"},
+		{"a <-> b", "a ~ b"},
+		{"∀x P(x)", "∀x P(x)"},
+		{"∃x ∀y R(x, f(y))", "∃x ∀y R(x, f(y))"},
+		{"∀x (P(x) → Q(x)) ∧ P(a)", "∀x (P(x) → Q(x)) ∧ P(a)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if got := node.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSpans(t *testing.T) {
+	node, err := Parse("a ∧ (b ∨ c)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary, ok := node.(*ast.BinaryNode)
+	if !ok {
+		t.Fatalf("Parse = %T, want a binary node", node)
+	}
+	tests := []struct {
+		node  ast.ASTNode
+		start int
+		end   int
+	}{
+		{binary, 0, 11},
+		{binary.Left, 0, 1},
+		{binary.Right, 4, 11},
+	}
+	for _, tt := range tests {
+		if span := tt.node.Span(); span.Start != tt.start || span.End != tt.end {
+			t.Errorf("span of %s = %v, want [%d, %d)", tt.node, span, tt.start, tt.end)
+		}
+	}
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"a & b ∨ c", "((a ∧ b) ∨ c)"},
+		{"a ∨ b & c", "(a ∨ (b ∧ c))"},
+		{"a -> b ∨ c", "(a → (b ∨ c))"},
+		{"a ~ b -> c", "(a ~ (b → c))"},
+		{"a -> b -> c", "(a → (b → c))"},
+		{"a <- b <- c", "((a ← b) ← c)"},
+		{"(a -> b) <- c", "((a → b) ← c)"},
+		{"a -> (b <- c)", "(a → (b ← c))"},
+		{"a <- (b -> c)", "(a ← (b → c))"},
+		{"a & b & c", "((a ∧ b) ∧ c)"},
+		{"!a & b", "(!a ∧ b)"},
+		{"a ~ b ⊕ c", "((a ~ b) ⊕ c)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if got := grouped(node); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMixedImplications(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		start   int
+	}{
+		{"a -> b <- c", "→ and ← cannot be mixed without parentheses", 2},
+		{"a <- b -> c", "← and → cannot be mixed without parentheses", 7},
+		{"a <- b <- c -> d", "← and → cannot be mixed without parentheses", 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			errs, ok := err.(ErrorList)
+			if !ok || len(errs) != 1 {
+				t.Fatalf("Parse(%q) = %v, want one error", tt.input, err)
+			}
+			if errs[0].Message != tt.message || errs[0].Span.Start != tt.start {
+				t.Errorf("Parse(%q) = %q at %d, want %q at %d", tt.input, errs[0].Message, errs[0].Span.Start, tt.message, tt.start)
+			}
+		})
+	}
+}
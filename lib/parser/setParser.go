@@ -0,0 +1,369 @@
+package parser
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"slices"
+)
+
+// setOperators is the operator table of the set language: intersection binds
+// tightest, then difference, then union and symmetric difference.
+var setOperators = map[lexer.SetTokenType]Operator{
+	lexer.INTERSECT: {BindingPower: 30, Associativity: LeftAssoc},
+	lexer.SUBSTRACT: {BindingPower: 20, Associativity: LeftAssoc},
+	lexer.UNION:     {BindingPower: 10, Associativity: LeftAssoc},
+	lexer.ADD:       {BindingPower: 10, Associativity: LeftAssoc},
+	lexer.SYMDIFF:   {BindingPower: 10, Associativity: LeftAssoc},
+}
+
+// setOperandStarts lists every token that can begin a set operand.
+var setOperandStarts = []lexer.SetTokenType{lexer.NAME, lexer.LBRACE, lexer.LGROUP, lexer.COMPLEMENT}
+
+// setOperatorTokens lists the binary set operators, loosest first.
+var setOperatorTokens = []lexer.SetTokenType{lexer.UNION, lexer.ADD, lexer.SYMDIFF, lexer.SUBSTRACT, lexer.INTERSECT}
+
+// SetParser parses programs of the set language:
+//
+//	<program>   ::= [<statement>] (";" [<statement>])*
+//	<statement> ::= NAME "=" <expr> | ELEMENT "∈" <expr> | <expr>
+//	<expr>      ::= <operand> (<operator> <operand>)*
+//	<operand>   ::= "¬" <operand> | <primary> "'"*
+//	<primary>   ::= NAME | "{" [ELEMENT ("," ELEMENT)*] "}" | "(" <expr> ")"
+//
+// Statements are separated by ";" or line breaks, operators are grouped by
+// setOperators. Unlike Parser it stops at the first syntax error.
+type SetParser struct {
+	Tokens  []lexer.Token[lexer.SetTokenType]
+	pos     int
+	lastEnd int
+}
+
+// ParseSets lexes and parses a set program. Definitions are returned as
+// *ast.SetDefinitionNode, membership tests as *ast.SetMembershipNode and
+// bare expressions as ast.SetNode.
+func ParseSets(input string) ([]ast.ASTNode, error) {
+	tokens, err := lexer.NewSetLexer(input).Lex()
+	if err != nil {
+		return nil, newLexErrors(err).locate(input)
+	}
+
+	p := &SetParser{Tokens: tokens}
+	statements, err := p.ParseProgram()
+	if err != nil {
+		return nil, ErrorList{err.(*ParseError)}.locate(input)
+	}
+	return statements, nil
+}
+
+// ParseSet lexes and parses a single set expression.
+func ParseSet(input string) (ast.SetNode, error) {
+	tokens, err := lexer.NewSetLexer(input).Lex()
+	if err != nil {
+		return nil, newLexErrors(err).locate(input)
+	}
+
+	p := &SetParser{Tokens: tokens}
+	expr, err := p.ParseSetExpression()
+	if err != nil {
+		return nil, ErrorList{err.(*ParseError)}.locate(input)
+	}
+	return expr, nil
+}
+
+// ParseSetIdentity lexes and parses an identity between two set
+// expressions, as in A \ (B ∪ C) = (A \ B) ∩ (A \ C).
+func ParseSetIdentity(input string) (left, right ast.SetNode, err error) {
+	tokens, err := lexer.NewSetLexer(input).Lex()
+	if err != nil {
+		return nil, nil, newLexErrors(err).locate(input)
+	}
+
+	p := &SetParser{Tokens: tokens}
+	left, right, err = p.ParseIdentity()
+	if err != nil {
+		return nil, nil, ErrorList{err.(*ParseError)}.locate(input)
+	}
+	return left, right, nil
+}
+
+func (p *SetParser) current() lexer.Token[lexer.SetTokenType] {
+	if p.pos >= len(p.Tokens) {
+		return p.eof()
+	}
+	return p.Tokens[p.pos]
+}
+
+func (p *SetParser) peek() lexer.Token[lexer.SetTokenType] {
+	if p.pos+1 >= len(p.Tokens) {
+		return p.eof()
+	}
+	return p.Tokens[p.pos+1]
+}
+
+func (p *SetParser) eof() lexer.Token[lexer.SetTokenType] {
+	end := 0
+	if len(p.Tokens) > 0 {
+		end = p.Tokens[len(p.Tokens)-1].Span().End
+	}
+	return lexer.Token[lexer.SetTokenType]{Type: lexer.EOF, Value: "", Pos: end}
+}
+
+func (p *SetParser) advance() {
+	if p.pos < len(p.Tokens) {
+		p.lastEnd = p.Tokens[p.pos].Span().End
+		p.pos++
+	}
+}
+
+func (p *SetParser) spanFrom(start int) lexer.Span {
+	return lexer.Span{Start: start, End: max(p.lastEnd, start)}
+}
+
+func (p *SetParser) expect(tokenType lexer.SetTokenType) error {
+	if p.current().Type != tokenType {
+		return p.unexpected(tokenType)
+	}
+	p.advance()
+	return nil
+}
+
+// unexpected builds the error for a current token that is not one of expected.
+func (p *SetParser) unexpected(expected ...lexer.SetTokenType) *ParseError {
+	token := p.current()
+
+	names := make([]string, len(expected))
+	var summary []string
+	for i, tokenType := range expected {
+		names[i] = tokenType.String()
+
+		name := names[i]
+		switch {
+		case slices.Equal(expected, setOperandStarts):
+			name = "set"
+		case slices.Contains(setOperatorTokens, tokenType):
+			name = "operator"
+		case tokenType == lexer.SEPARATOR:
+			name = "line break"
+		case tokenType == lexer.EOF:
+			name = "end of input"
+		}
+		if !slices.Contains(summary, name) {
+			summary = append(summary, name)
+		}
+	}
+
+	found := "end of input"
+	switch token.Type {
+	case lexer.EOF:
+	case lexer.SEPARATOR:
+		found = "line break"
+		if token.Value == ";" {
+			found = "';'"
+		}
+	default:
+		found = fmt.Sprintf("'%s'", token.Value)
+	}
+
+	return &ParseError{
+		Message:  fmt.Sprintf("expected %s, found %s", joinAlternatives(summary), found),
+		Span:     token.Span(),
+		Expected: names,
+		Found:    token.Value,
+	}
+}
+
+// <program> ::= [<statement>] (";" [<statement>])*
+func (p *SetParser) ParseProgram() ([]ast.ASTNode, error) {
+	p.pos = 0
+	var statements []ast.ASTNode
+
+	for p.current().Type != lexer.EOF {
+		if p.current().Type == lexer.SEPARATOR {
+			p.advance()
+			continue
+		}
+
+		statement, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+
+		if p.current().Type != lexer.EOF && p.current().Type != lexer.SEPARATOR {
+			return nil, p.unexpected(slices.Concat(setOperatorTokens, []lexer.SetTokenType{lexer.SEPARATOR, lexer.EOF})...)
+		}
+	}
+
+	return statements, nil
+}
+
+// ParseSetExpression parses input consisting of exactly one set expression.
+func (p *SetParser) ParseSetExpression() (ast.SetNode, error) {
+	p.pos = 0
+	expr, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.current().Type != lexer.EOF {
+		return nil, p.unexpected(slices.Concat(setOperatorTokens, []lexer.SetTokenType{lexer.EOF})...)
+	}
+	return expr, nil
+}
+
+// <identity> ::= <expr> "=" <expr>
+func (p *SetParser) ParseIdentity() (left, right ast.SetNode, err error) {
+	p.pos = 0
+	left, err = p.parseExpr(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.current().Type != lexer.ASSIGN {
+		return nil, nil, p.unexpected(slices.Concat(setOperatorTokens, []lexer.SetTokenType{lexer.ASSIGN})...)
+	}
+	p.advance() // consume "="
+
+	right, err = p.parseExpr(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.current().Type != lexer.EOF {
+		return nil, nil, p.unexpected(slices.Concat(setOperatorTokens, []lexer.SetTokenType{lexer.EOF})...)
+	}
+	return left, right, nil
+}
+
+// <statement> ::= NAME "=" <expr> | ELEMENT "∈" <expr> | <expr>
+func (p *SetParser) parseStatement() (ast.ASTNode, error) {
+	if p.current().Type == lexer.ELEMENT {
+		start := p.current().Pos
+		element := p.current().Value
+		p.advance() // consume element
+		if err := p.expect(lexer.IN); err != nil {
+			return nil, err
+		}
+
+		set, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		return at(ast.NewSetMembershipNode(element, set), p.spanFrom(start)), nil
+	}
+
+	if p.current().Type == lexer.NAME && p.peek().Type == lexer.ASSIGN {
+		start := p.current().Pos
+		name := p.current().Value
+		p.advance() // consume name
+		p.advance() // consume "="
+
+		value, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		return at(ast.NewSetDefinitionNode(name, value), p.spanFrom(start)), nil
+	}
+	return p.parseExpr(0)
+}
+
+// parseExpr parses an operand followed by every set operator that binds
+// tighter than minPower, the same way Parser.parseExpr does.
+func (p *SetParser) parseExpr(minPower int) (ast.SetNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator := p.current().Type
+		info, ok := setOperators[operator]
+		if !ok || info.BindingPower <= minPower {
+			return left, nil
+		}
+		p.advance() // consume the operator
+		right, err := p.parseExpr(info.rightPower())
+		if err != nil {
+			return nil, err
+		}
+		left = at(ast.NewSetBinaryNode(operator, left, right), left.Span().Cover(right.Span()))
+	}
+}
+
+// <operand> ::= "¬" <operand> | <primary> "'"*
+func (p *SetParser) parseOperand() (ast.SetNode, error) {
+	token := p.current()
+
+	if token.Type == lexer.COMPLEMENT {
+		p.advance() // consume the prefix complement
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return at(ast.NewSetComplementNode(operand), p.spanFrom(token.Pos)), nil
+	}
+
+	operand, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.current().Type == lexer.COMPLEMENT {
+		p.advance() // consume the postfix complement
+		operand = at(ast.NewSetComplementNode(operand), p.spanFrom(token.Pos))
+	}
+	return operand, nil
+}
+
+// <primary> ::= NAME | "{" [ELEMENT ("," ELEMENT)*] "}" | "(" <expr> ")"
+func (p *SetParser) parsePrimary() (ast.SetNode, error) {
+	token := p.current()
+
+	switch token.Type {
+	case lexer.NAME:
+		p.advance()
+		return at(ast.NewSetNameNode(token.Value), token.Span()), nil
+	case lexer.LBRACE:
+		return p.parseLiteral()
+	case lexer.LGROUP:
+		p.advance() // consume "("
+		expr, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(lexer.RGROUP); err != nil {
+			return nil, err
+		}
+		return at(ast.NewSetGroupingNode(expr), p.spanFrom(token.Pos)), nil
+	default:
+		return nil, p.unexpected(setOperandStarts...)
+	}
+}
+
+// <literal> ::= "{" [ELEMENT ("," ELEMENT)*] "}"
+func (p *SetParser) parseLiteral() (ast.SetNode, error) {
+	start := p.current().Pos
+	p.advance() // consume "{"
+
+	var elements []string
+	if p.current().Type == lexer.ELEMENT {
+		elements = append(elements, p.current().Value)
+		p.advance()
+
+		for p.current().Type == lexer.COMMA {
+			p.advance() // consume ","
+			if p.current().Type != lexer.ELEMENT {
+				return nil, p.unexpected(lexer.ELEMENT)
+			}
+			elements = append(elements, p.current().Value)
+			p.advance()
+		}
+	}
+
+	if p.current().Type != lexer.RBRACE {
+		if len(elements) == 0 {
+			return nil, p.unexpected(lexer.ELEMENT, lexer.RBRACE)
+		}
+		return nil, p.unexpected(lexer.COMMA, lexer.RBRACE)
+	}
+	p.advance() // consume "}"
+
+	return at(ast.NewSetLiteralNode(elements...), p.spanFrom(start)), nil
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"errors"
+	"logicka/lib/ast"
+	"testing"
+)
+
+// groupedSet prints node with every binary operation in parentheses.
+func groupedSet(node ast.ASTNode) string {
+	switch n := node.(type) {
+	case *ast.SetBinaryNode:
+		return "(" + groupedSet(n.Left) + " " + n.Operator.String() + " " + groupedSet(n.Right) + ")"
+	case *ast.SetGroupingNode:
+		return groupedSet(n.Expr)
+	default:
+		return node.String()
+	}
+}
+
+func TestParseSetExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"A", "A"},
+		{"A ∪ B ∪ C", "((A ∪ B) ∪ C)"},
+		{"A \\ B \\ C", "((A \\ B) \\ C)"},
+		{"A ∪ (B ∩ C)", "(A ∪ (B ∩ C))"},
+		{"!A ∪ B", "(A' ∪ B)"},
+		{"{1, 2} ∩ A", "({1, 2} ∩ A)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			statements, err := ParseSets(tt.input)
+			if err != nil {
+				t.Fatalf("ParseSets(%q): %v", tt.input, err)
+			}
+			if len(statements) != 1 {
+				t.Fatalf("ParseSets(%q) = %d statements, want 1", tt.input, len(statements))
+			}
+			if got := groupedSet(statements[0]); got != tt.want {
+				t.Errorf("ParseSets(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSets(t *testing.T) {
+	statements, err := ParseSets("X = {1, 2}\nX ∩ A\n1 ∈ A ∩ B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"X = {1, 2}", "X ∩ A", "1 ∈ A ∩ B"}
+	if len(statements) != len(want) {
+		t.Fatalf("ParseSets = %d statements, want %d", len(statements), len(want))
+	}
+	for i, statement := range statements {
+		if statement.String() != want[i] {
+			t.Errorf("statement %d = %s, want %s", i, statement, want[i])
+		}
+	}
+}
+
+func TestParseSetErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		start   int
+	}{
+		{"A ∪", "expected set, found end of input", 3},
+		{"{1, 2", "expected , or }, found end of input", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseSets(tt.input)
+			var errs ErrorList
+			if !errors.As(err, &errs) || len(errs) != 1 {
+				t.Fatalf("ParseSets(%q) = %v, want one error", tt.input, err)
+			}
+			if parseErr := errs[0]; parseErr.Message != tt.message || parseErr.Span.Start != tt.start {
+				t.Errorf("ParseSets(%q) = %q at %d, want %q at %d", tt.input, parseErr.Message, parseErr.Span.Start, tt.message, tt.start)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+// Package qbf evaluates quantified boolean formulas, formulas whose
+// quantifiers range over truth values, as ∀p ∃q (p ↔ q), and explains the
+// value by a winning strategy.
+package qbf
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/visitor"
+	"maps"
+	"slices"
+)
+
+// QuantifierError is returned for a quantifier that does not range over
+// truth values: one that is bounded or whose variable is a term.
+type QuantifierError struct {
+	Quantifier string
+	Span       lexer.Span
+}
+
+func (e QuantifierError) Error() string {
+	return fmt.Sprintf("quantifier %s at position %d does not range over truth values", e.Quantifier, e.Span.Start)
+}
+
+// StrategyRow is one entry of a strategy: given the values of the variables
+// the strategy depends on, in the order of Strategy.Depends, the player sets
+// the variable to Choice.
+type StrategyRow struct {
+	Values []bool
+	Choice bool
+}
+
+// Strategy tells how the player of one quantifier chooses its variable. For
+// an ∃ it is a Skolem function of the ∀ variables quantified before it, and
+// for a ∀ the other way round.
+type Strategy struct {
+	Quantifier string // ∀ or ∃
+	Variable   string
+	Depends    []string
+	Rows       []StrategyRow
+}
+
+// Result is the value of a quantified boolean formula. The formula is
+// evaluated as a game in its prenex normal form Prenex, where the ∃ player
+// tries to make the matrix true and the ∀ player tries to make it false;
+// Winner is the player who can force the outcome, whatever the other one
+// does, by following Strategies, one per quantifier of the winner.
+// Quantified variables may have been renamed in Prenex.
+type Result struct {
+	Formula    string
+	Prenex     string
+	Value      bool
+	Winner     string
+	Strategies []Strategy
+}
+
+// Solve evaluates formula. Its free variables take the values given in
+// values; every quantifier must range over truth values.
+func Solve(formula ast.ASTNode, values map[string]bool) (*Result, error) {
+	if values == nil {
+		values = make(map[string]bool)
+	}
+	if err := checkQuantifiers(formula); err != nil {
+		return nil, err
+	}
+
+	prenex, _, err := visitor.NewPrenexConverter().Convert(formula)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &game{values: values}
+	matrix := prenex
+	for {
+		if grouping, ok := matrix.(*ast.GroupingNode); ok {
+			matrix = grouping.Expr
+			continue
+		}
+		q, ok := matrix.(*ast.QuantifierNode)
+		if !ok {
+			break
+		}
+		g.prefix = append(g.prefix, q)
+		matrix = q.Body
+	}
+	g.matrix = matrix
+
+	for _, name := range ast.FreeVariables(matrix) {
+		if _, ok := values[name]; !ok && !slices.ContainsFunc(g.prefix, func(q *ast.QuantifierNode) bool { return q.Variable == name }) {
+			return nil, visitor.UnboundVariableError{Name: name, Span: occurrence(formula, name)}
+		}
+	}
+
+	value, err := g.evaluate(0, maps.Clone(values))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Formula:    formula.String(),
+		Prenex:     prenex.String(),
+		Value:      value,
+		Winner:     lexer.FORALL.String(),
+		Strategies: []Strategy{},
+	}
+	winner := lexer.FORALL
+	if value {
+		winner = lexer.EXISTS
+		result.Winner = lexer.EXISTS.String()
+	}
+
+	strategies := make([]*Strategy, len(g.prefix))
+	for i, q := range g.prefix {
+		if q.Type != winner {
+			continue
+		}
+		strategies[i] = &Strategy{Quantifier: q.Type.String(), Variable: q.Variable, Depends: []string{}, Rows: []StrategyRow{}}
+		for _, other := range g.prefix[:i] {
+			if other.Type != winner {
+				strategies[i].Depends = append(strategies[i].Depends, other.Variable)
+			}
+		}
+	}
+	if err := g.play(0, maps.Clone(values), winner, strategies); err != nil {
+		return nil, err
+	}
+
+	for _, strategy := range strategies {
+		if strategy == nil {
+			continue
+		}
+		// Rows go in truth table order, true before false.
+		slices.SortFunc(strategy.Rows, func(a, b StrategyRow) int {
+			for i := range a.Values {
+				if a.Values[i] != b.Values[i] {
+					if a.Values[i] {
+						return -1
+					}
+					return 1
+				}
+			}
+			return 0
+		})
+		result.Strategies = append(result.Strategies, *strategy)
+	}
+	return result, nil
+}
+
+// game is a quantified boolean formula in prenex normal form.
+type game struct {
+	values map[string]bool
+	prefix []*ast.QuantifierNode
+	matrix ast.ASTNode
+}
+
+// evaluate returns the value of the formula from the i-th quantifier on,
+// with the variables before it set as in assignment.
+func (g *game) evaluate(i int, assignment map[string]bool) (bool, error) {
+	if i == len(g.prefix) {
+		return g.evaluateMatrix(assignment)
+	}
+
+	q := g.prefix[i]
+	// ∃ is decided by the first true value and ∀ by the first false one.
+	decisive := q.Type == lexer.EXISTS
+	for _, value := range []bool{true, false} {
+		assignment[q.Variable] = value
+		result, err := g.evaluate(i+1, assignment)
+		if err != nil {
+			return false, err
+		}
+		if result == decisive {
+			return decisive, nil
+		}
+	}
+	return !decisive, nil
+}
+
+// play follows the game from the i-th quantifier on. The winner makes the
+// first choice that keeps the outcome, which is recorded in its strategy;
+// the other player's choices are all tried.
+func (g *game) play(i int, assignment map[string]bool, winner lexer.BooleanTokenType, strategies []*Strategy) error {
+	if i == len(g.prefix) {
+		return nil
+	}
+
+	q := g.prefix[i]
+	if q.Type != winner {
+		for _, value := range []bool{true, false} {
+			assignment[q.Variable] = value
+			if err := g.play(i+1, assignment, winner, strategies); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	goal := winner == lexer.EXISTS
+	for _, value := range []bool{true, false} {
+		assignment[q.Variable] = value
+		result, err := g.evaluate(i+1, assignment)
+		if err != nil {
+			return err
+		}
+		if result != goal {
+			continue
+		}
+
+		strategy := strategies[i]
+		row := StrategyRow{Values: make([]bool, len(strategy.Depends)), Choice: value}
+		for j, variable := range strategy.Depends {
+			row.Values[j] = assignment[variable]
+		}
+		strategy.Rows = append(strategy.Rows, row)
+		return g.play(i+1, assignment, winner, strategies)
+	}
+	return nil
+}
+
+func (g *game) evaluateMatrix(assignment map[string]bool) (bool, error) {
+	solver := visitor.NewBooleanSolver(&visitor.EvaluationContext{Variables: assignment})
+	table, err := solver.Solve(g.matrix)
+	if err != nil {
+		return false, err
+	}
+	// Every variable of the matrix is assigned, so the table has one row.
+	return table[0].Result, nil
+}
+
+// checkQuantifiers returns an error for the first quantifier of node that
+// does not range over truth values.
+func checkQuantifiers(node ast.ASTNode) error {
+	if q, ok := node.(*ast.QuantifierNode); ok && !q.Propositional() {
+		return QuantifierError{Quantifier: q.String(), Span: q.Span()}
+	}
+	if traversable, ok := node.(ast.Traversable); ok {
+		for _, child := range traversable.Children() {
+			if err := checkQuantifiers(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// occurrence returns the span of the first occurrence of the variable name
+// in node.
+func occurrence(node ast.ASTNode, name string) lexer.Span {
+	if variable, ok := node.(*ast.VariableNode); ok && variable.Name == name {
+		return variable.Span()
+	}
+	if traversable, ok := node.(ast.Traversable); ok {
+		for _, child := range traversable.Children() {
+			if span := occurrence(child, name); !span.IsZero() {
+				return span
+			}
+		}
+	}
+	return lexer.Span{}
+}
@@ -0,0 +1,71 @@
+package qbf
+
+import (
+	"logicka/lib/parser"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		expr   string
+		values map[string]bool
+		value  bool
+		winner string
+	}{
+		{"A x E y (x <-> y)", nil, true, "∃"},
+		{"E y A x (x <-> y)", nil, false, "∀"},
+		{"A x (x ∨ !x)", nil, true, "∃"},
+		{"E x (x ∧ a)", map[string]bool{"a": false}, false, "∀"},
+		{"E x (x ∧ a)", map[string]bool{"a": true}, true, "∃"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			node, err := parser.Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			result, err := Solve(node, tt.values)
+			if err != nil {
+				t.Fatalf("Solve(%q): %v", tt.expr, err)
+			}
+			if result.Value != tt.value || result.Winner != tt.winner {
+				t.Errorf("Solve(%q) = %v won by %s, want %v won by %s", tt.expr, result.Value, result.Winner, tt.value, tt.winner)
+			}
+		})
+	}
+}
+
+func TestSolveStrategy(t *testing.T) {
+	node, err := parser.Parse("A x E y (x <-> y)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Solve(node, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Strategies) != 1 {
+		t.Fatalf("got %d strategies, want 1", len(result.Strategies))
+	}
+	strategy := result.Strategies[0]
+	if strategy.Variable != "y" || len(strategy.Depends) != 1 || strategy.Depends[0] != "x" {
+		t.Fatalf("strategy for %s depends on %v, want y on [x]", strategy.Variable, strategy.Depends)
+	}
+	for _, row := range strategy.Rows {
+		if row.Choice != row.Values[0] {
+			t.Errorf("y = %v when x = %v, want y = x", row.Choice, row.Values[0])
+		}
+	}
+}
+
+func TestSolveUnbound(t *testing.T) {
+	node, err := parser.Parse("A x (x ∨ a)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Solve(node, nil); err == nil {
+		t.Error("Solve with the free variable a unset succeeded, want an error")
+	}
+}
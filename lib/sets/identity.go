@@ -0,0 +1,123 @@
+// Package sets answers questions about set expressions by translating them to
+// propositional formulas over membership variables and solving those.
+package sets
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/visitor"
+	"maps"
+	"slices"
+)
+
+// Region is a region of the Venn diagram of some sets: the elements that
+// belong to every set in Inside and to none of the sets in Outside. Bit i of
+// Index is set when the i-th of the sets, in diagram order, is in Inside.
+type Region struct {
+	Index   int
+	Inside  []string
+	Outside []string
+}
+
+// Counterexample is a region on which the two sides of an identity differ.
+type Counterexample struct {
+	Region  Region
+	InLeft  bool // whether the left side contains the region
+	InRight bool // whether the right side contains the region
+}
+
+// IdentityResult tells whether an identity holds for all sets. When it does
+// not, Counterexample names a region that belongs to one side only.
+type IdentityResult struct {
+	Left           string
+	Right          string
+	Formula        string // the membership formula that was checked for validity
+	Holds          bool
+	Counterexample *Counterexample
+}
+
+// ProveIdentity decides whether left = right holds for arbitrary sets. Both
+// sides are translated with visitor.SetTranslator and the identity holds when
+// the equivalence of the translations is true on every row of its truth table.
+func ProveIdentity(left, right ast.SetNode) (*IdentityResult, error) {
+	translator := visitor.NewSetTranslator()
+	leftFormula, err := translator.Translate(left)
+	if err != nil {
+		return nil, err
+	}
+	rightFormula, err := translator.Translate(right)
+	if err != nil {
+		return nil, err
+	}
+
+	formula := ast.NewBinaryNode(lexer.EQUIV, group(leftFormula), group(rightFormula))
+	solver := visitor.NewBooleanSolver(&visitor.EvaluationContext{Variables: map[string]bool{}})
+	table, err := solver.Solve(formula)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IdentityResult{
+		Left:    left.String(),
+		Right:   right.String(),
+		Formula: formula.String(),
+		Holds:   true,
+	}
+
+	for _, entry := range table {
+		if entry.Result {
+			continue
+		}
+
+		result.Holds = false
+		values := make(map[string]bool, len(entry.Variables))
+		for _, variable := range entry.Variables {
+			values[variable.Name] = variable.Value
+		}
+
+		names := slices.Sorted(maps.Keys(values))
+		result.Counterexample = &Counterexample{Region: regionOf(names, values)}
+		result.Counterexample.InLeft, err = evaluate(leftFormula, values)
+		if err != nil {
+			return nil, err
+		}
+		result.Counterexample.InRight, err = evaluate(rightFormula, values)
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	return result, nil
+}
+
+// evaluate returns the value of formula when every variable is fixed by values.
+func evaluate(formula ast.ASTNode, values map[string]bool) (bool, error) {
+	solver := visitor.NewBooleanSolver(&visitor.EvaluationContext{Variables: values})
+	table, err := solver.Solve(formula)
+	if err != nil {
+		return false, err
+	}
+	return len(table) > 0 && table[0].Result, nil
+}
+
+// regionOf returns the region of the diagram of names selected by values.
+func regionOf(names []string, values map[string]bool) Region {
+	region := Region{Inside: []string{}, Outside: []string{}}
+	for i, name := range names {
+		if values[name] {
+			region.Index |= 1 << i
+			region.Inside = append(region.Inside, name)
+		} else {
+			region.Outside = append(region.Outside, name)
+		}
+	}
+	return region
+}
+
+func group(node ast.ASTNode) ast.ASTNode {
+	if _, ok := node.(*ast.BinaryNode); ok {
+		return ast.NewGroupingNode(node)
+	}
+	return node
+}
@@ -0,0 +1,103 @@
+package sets
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/visitor"
+	"slices"
+	"strings"
+)
+
+// MaxVennSets is the largest number of sets a Venn diagram is drawn for.
+const MaxVennSets = 4
+
+// TooManySetsError is returned for diagrams of more than MaxVennSets sets.
+type TooManySetsError struct {
+	Sets []string
+}
+
+func (e TooManySetsError) Error() string {
+	return fmt.Sprintf("a Venn diagram can show at most %d sets, got %d: %s",
+		MaxVennSets, len(e.Sets), strings.Join(e.Sets, ", "))
+}
+
+// Diagram is the Venn diagram of an expression. Regions lists all 2^n regions
+// of the diagram of Sets ordered by Index, and Covered[i] tells whether the
+// expression contains Regions[i].
+type Diagram struct {
+	Expression string
+	Sets       []string
+	Regions    []Region
+	Covered    []bool
+}
+
+// Venn computes which regions of the Venn diagram of names the expression
+// covers. When names is empty the diagram is drawn for the sets the
+// expression mentions, in alphabetical order. Sets of the expression missing
+// from names are added after them.
+func Venn(expr ast.SetNode, names []string) (*Diagram, error) {
+	formula, err := visitor.NewSetTranslator().Translate(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// The variables of the formula are the sets of the expression. The
+	// diagram is checked for size before the table is computed.
+	mentioned := ast.FreeVariables(formula)
+	slices.Sort(mentioned)
+
+	sets := []string{}
+	for _, name := range slices.Concat(names, mentioned) {
+		if !slices.Contains(sets, name) {
+			sets = append(sets, name)
+		}
+	}
+	names = sets
+	if len(names) > MaxVennSets {
+		return nil, TooManySetsError{Sets: names}
+	}
+
+	// Every row of the table assigns the sets of the expression. Rows are
+	// indexed by the bitmask of the true ones.
+	solver := visitor.NewBooleanSolver(&visitor.EvaluationContext{Variables: map[string]bool{}})
+	table, err := solver.Solve(formula)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[int]bool, len(table))
+	for _, entry := range table {
+		row := 0
+		for _, variable := range entry.Variables {
+			if variable.Value {
+				row |= 1 << slices.Index(mentioned, variable.Name)
+			}
+		}
+		results[row] = entry.Result
+	}
+
+	diagram := &Diagram{
+		Expression: expr.String(),
+		Sets:       names,
+		Regions:    make([]Region, 0, 1<<len(names)),
+		Covered:    make([]bool, 0, 1<<len(names)),
+	}
+	for index := range 1 << len(names) {
+		values := make(map[string]bool, len(names))
+		for i, name := range names {
+			values[name] = index&(1<<i) != 0
+		}
+
+		row := 0
+		for i, name := range mentioned {
+			if values[name] {
+				row |= 1 << i
+			}
+		}
+
+		diagram.Regions = append(diagram.Regions, regionOf(names, values))
+		diagram.Covered = append(diagram.Covered, results[row])
+	}
+
+	return diagram, nil
+}
@@ -0,0 +1,26 @@
+package sets
+
+import (
+	"encoding/json"
+	"logicka/lib/parser"
+	"strings"
+	"testing"
+)
+
+func TestVennWithoutSets(t *testing.T) {
+	node, err := parser.ParseSet("{}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diagram, err := Venn(node, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(diagram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"Sets":[]`) {
+		t.Errorf("diagram = %s, want an empty list of sets", data)
+	}
+}
@@ -3,6 +3,7 @@ package base
 import (
 	"fmt"
 	"logicka/lib/ast"
+	"logicka/lib/lexer"
 )
 
 type Rule interface {
@@ -10,7 +11,7 @@ type Rule interface {
 	Apply(node ast.ASTNode) (ast.ASTNode, error)
 	Name() string
 	Applications() []RuleApplication
-	RecordApplication(description, before, after string)
+	RecordApplication(description string, before, after ast.ASTNode)
 	ClearApplications()
 }
 
@@ -19,6 +20,7 @@ type RuleApplication struct {
 	Description string
 	Before      string
 	After       string
+	Span        lexer.Span // source span of the rewritten subexpression
 }
 
 func (ra RuleApplication) String() string {
@@ -54,12 +56,13 @@ func (r *BaseRule) Applications() []RuleApplication {
 	return r.applications
 }
 
-func (r *BaseRule) RecordApplication(description, before, after string) {
+func (r *BaseRule) RecordApplication(description string, before, after ast.ASTNode) {
 	r.applications = append(r.applications, RuleApplication{
 		Name:        r.name,
 		Description: description,
-		Before:      before,
-		After:       after,
+		Before:      before.String(),
+		After:       after.String(),
+		Span:        before.Span(),
 	})
 }
 
@@ -31,9 +31,10 @@ func (rs *RuleSet) Apply(node ast.ASTNode) (ast.ASTNode, error) {
 		if simplified.Equals(current) {
 			continue
 		}
+		ast.InheritSpan(simplified, current.Span())
 
 		if rule.Name() != "Объединение в цепочку операторов" {
-			rule.RecordApplication("Описание", current.String(), simplified.String())
+			rule.RecordApplication("Описание", current, simplified)
 		}
 		current = simplified
 		appliedRules = append(appliedRules, rule)
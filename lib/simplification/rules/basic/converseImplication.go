@@ -0,0 +1,32 @@
+package basic
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/simplification/rules/base"
+)
+
+type ConverseImplicationRule struct {
+	base.BaseRule
+}
+
+func NewConverseImplicationRule() *ConverseImplicationRule {
+	return &ConverseImplicationRule{
+		BaseRule: *base.NewBaseRule("Упрощение обратной импликации"),
+	}
+}
+
+func (r *ConverseImplicationRule) CanApply(node ast.ASTNode) bool {
+	binary, ok := node.(*ast.BinaryNode)
+	if !ok {
+		return false
+	}
+
+	return binary.Operator == lexer.CONV
+}
+
+func (r *ConverseImplicationRule) Apply(node ast.ASTNode) (ast.ASTNode, error) {
+	binary := node.(*ast.BinaryNode)
+
+	return ast.NewBinaryNode(lexer.DISJ, group(binary.Left), negate(binary.Right)), nil
+}
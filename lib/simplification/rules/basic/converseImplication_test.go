@@ -0,0 +1,38 @@
+package basic
+
+import (
+	"logicka/lib/parser"
+	"testing"
+)
+
+func TestConverseImplicationRule(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"a <- b", "a ∨ !b"},
+		{"a <- (b ∧ c)", "a ∨ !(b ∧ c)"},
+		{"(a → b) <- c", "(a → b) ∨ !c"},
+		{"(a ⊕ b) <- (c ∨ d)", "(a ⊕ b) ∨ !(c ∨ d)"},
+	}
+
+	rule := NewConverseImplicationRule()
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if !rule.CanApply(node) {
+				t.Fatalf("rule does not apply to %s", tt.input)
+			}
+			result, err := rule.Apply(node)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.String(); got != tt.want {
+				t.Errorf("Apply(%s) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
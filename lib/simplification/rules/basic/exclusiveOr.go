@@ -0,0 +1,53 @@
+package basic
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/simplification/rules/base"
+)
+
+type ExclusiveOrRule struct {
+	base.BaseRule
+}
+
+func NewExclusiveOrRule() *ExclusiveOrRule {
+	return &ExclusiveOrRule{
+		BaseRule: *base.NewBaseRule("Упрощение исключающего ИЛИ"),
+	}
+}
+
+func (r *ExclusiveOrRule) CanApply(node ast.ASTNode) bool {
+	binary, ok := node.(*ast.BinaryNode)
+	if !ok {
+		return false
+	}
+
+	return binary.Operator == lexer.XOR
+}
+
+func (r *ExclusiveOrRule) Apply(node ast.ASTNode) (ast.ASTNode, error) {
+	binary := node.(*ast.BinaryNode)
+
+	return ast.NewBinaryNode(
+		lexer.DISJ,
+		ast.NewGroupingNode(ast.NewBinaryNode(lexer.CONJ, group(binary.Left), negate(binary.Right))),
+		ast.NewGroupingNode(ast.NewBinaryNode(lexer.CONJ, negate(binary.Left), group(binary.Right))),
+	), nil
+}
+
+// group parenthesises compound nodes so that they keep their meaning when
+// they become an operand of another operator.
+func group(node ast.ASTNode) ast.ASTNode {
+	switch node.(type) {
+	case *ast.BinaryNode, *ast.ChainNode:
+		return ast.NewGroupingNode(node)
+	default:
+		return node
+	}
+}
+
+// negate builds the negation of node, parenthesising compound operands so
+// that De Morgan's law can pick the result up.
+func negate(node ast.ASTNode) ast.ASTNode {
+	return ast.NewUnaryNode(lexer.NEG, group(node))
+}
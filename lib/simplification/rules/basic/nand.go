@@ -0,0 +1,32 @@
+package basic
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/simplification/rules/base"
+)
+
+type NandRule struct {
+	base.BaseRule
+}
+
+func NewNandRule() *NandRule {
+	return &NandRule{
+		BaseRule: *base.NewBaseRule("Упрощение штриха Шеффера"),
+	}
+}
+
+func (r *NandRule) CanApply(node ast.ASTNode) bool {
+	binary, ok := node.(*ast.BinaryNode)
+	if !ok {
+		return false
+	}
+
+	return binary.Operator == lexer.NAND
+}
+
+func (r *NandRule) Apply(node ast.ASTNode) (ast.ASTNode, error) {
+	binary := node.(*ast.BinaryNode)
+
+	return negate(ast.NewBinaryNode(lexer.CONJ, group(binary.Left), group(binary.Right))), nil
+}
@@ -0,0 +1,32 @@
+package basic
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/simplification/rules/base"
+)
+
+type NorRule struct {
+	base.BaseRule
+}
+
+func NewNorRule() *NorRule {
+	return &NorRule{
+		BaseRule: *base.NewBaseRule("Упрощение стрелки Пирса"),
+	}
+}
+
+func (r *NorRule) CanApply(node ast.ASTNode) bool {
+	binary, ok := node.(*ast.BinaryNode)
+	if !ok {
+		return false
+	}
+
+	return binary.Operator == lexer.NOR
+}
+
+func (r *NorRule) Apply(node ast.ASTNode) (ast.ASTNode, error) {
+	binary := node.(*ast.BinaryNode)
+
+	return negate(ast.NewBinaryNode(lexer.DISJ, group(binary.Left), group(binary.Right))), nil
+}
@@ -12,6 +12,10 @@ func CreateBasicRules() []base.Rule {
 		NewLiteralNegationRule(),
 		NewImplicationRule(),
 		NewEquivalenceRule(),
+		NewConverseImplicationRule(),
+		NewExclusiveOrRule(),
+		NewNandRule(),
+		NewNorRule(),
 	}
 }
 
@@ -3,6 +3,7 @@ package chain
 import (
 	"logicka/lib/ast"
 	"logicka/lib/simplification/rules/base"
+	"slices"
 )
 
 type DuplicatesRule struct {
@@ -35,19 +36,19 @@ func (r *DuplicatesRule) Apply(node ast.ASTNode) (ast.ASTNode, error) {
 	}
 }
 
+// collectUniqueOperands drops the operands equal to an earlier one and keeps
+// the rest in order. Operands are grouped by hash so that only those in the
+// same group need to be compared.
 func collectUniqueOperands(operands []ast.ASTNode) []ast.ASTNode {
-	unique := make(map[uint64]ast.ASTNode, len(operands)/2)
+	groups := make(map[uint64][]ast.ASTNode, len(operands))
+	result := make([]ast.ASTNode, 0, len(operands))
 
 	for _, operand := range operands {
 		hash := operand.Hash()
-		if _, ok := unique[hash]; ok {
+		if slices.ContainsFunc(groups[hash], operand.Equals) {
 			continue
 		}
-		unique[hash] = operand
-	}
-
-	result := make([]ast.ASTNode, 0, len(unique))
-	for _, operand := range unique {
+		groups[hash] = append(groups[hash], operand)
 		result = append(result, operand)
 	}
 
@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"errors"
+	"logicka/lib/ast"
+	"logicka/lib/parser"
+	"logicka/lib/visitor"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// TruthTableEvent is the runtime event StreamTruthTable emits its chunks with.
+const TruthTableEvent = "truth-table"
+
+// TruthTableChunk is a page of a streamed truth table. Stream is the ID
+// StreamTruthTable returned; Last marks the final chunk of the stream, which
+// carries Error instead of a page when computing the table failed.
+type TruthTableChunk struct {
+	Stream int64
+	Page   *visitor.TruthTablePage
+	Last   bool
+	Error  string
+}
+
+// StreamTruthTable computes the truth table of expr in the background and
+// emits the rows that pass filter as TruthTableEvent events of pageSize rows
+// each. It returns the ID of the stream at once; a stream started before is
+// stopped. The formula is not simplified and order orders the variables, as
+// in GetTruthTablePage.
+func (l *Logicka) StreamTruthTable(expr string, values map[string]bool, order []string, pageSize int, filter visitor.RowFilter) (int64, error) {
+	if l.ctx == nil {
+		return 0, errors.New("streaming needs the application context")
+	}
+	node, err := parser.Parse(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	id := l.stream.Add(1)
+	ctx := &visitor.EvaluationContext{Variables: values, Order: order}
+	go l.streamRows(id, node, ctx, pageSize, filter, func(chunk TruthTableChunk) {
+		runtime.EventsEmit(l.ctx, TruthTableEvent, chunk)
+	})
+	return id, nil
+}
+
+// streamRows computes the table of node page by page and hands every chunk
+// to emit, until the table ends or id is no longer the current stream. Pages
+// that stopped at visitor.MaxScanWords without finding a row are not emitted.
+func (l *Logicka) streamRows(id int64, node ast.ASTNode, ctx *visitor.EvaluationContext, pageSize int, filter visitor.RowFilter, emit func(TruthTableChunk)) {
+	solver := visitor.NewBooleanSolver(ctx)
+	solver.SetCancel(func() bool { return l.stream.Load() != id })
+	from := 0
+	for l.stream.Load() == id {
+		page, err := solver.SolveRows(node, from, pageSize, filter)
+		if errors.Is(err, visitor.ErrCancelled) {
+			return
+		}
+		if err != nil {
+			emit(TruthTableChunk{Stream: id, Last: true, Error: err.Error()})
+			return
+		}
+		if len(page.Rows) > 0 || !page.More {
+			emit(TruthTableChunk{Stream: id, Page: page, Last: !page.More})
+		}
+		if !page.More {
+			return
+		}
+		from = page.Next
+	}
+}
+
+// CancelTruthTableStream stops the truth table being streamed, if any.
+func (l *Logicka) CancelTruthTableStream() {
+	l.stream.Add(1)
+}
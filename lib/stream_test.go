@@ -0,0 +1,98 @@
+package lib
+
+import (
+	"logicka/lib/parser"
+	"logicka/lib/visitor"
+	"slices"
+	"testing"
+)
+
+// collect streams the table of input and returns the chunks it emits.
+func collect(t *testing.T, l *Logicka, input string, pageSize int, filter visitor.RowFilter) []TruthTableChunk {
+	t.Helper()
+	node, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+
+	id := l.stream.Add(1)
+	var chunks []TruthTableChunk
+	l.streamRows(id, node, visitor.NewEvaluationContext(), pageSize, filter, func(chunk TruthTableChunk) {
+		if chunk.Stream != id {
+			t.Errorf("chunk of stream %d, want %d", chunk.Stream, id)
+		}
+		chunks = append(chunks, chunk)
+	})
+	return chunks
+}
+
+func TestStreamRows(t *testing.T) {
+	const input = "(a ∧ b) ⊕ (c ∨ !d) → (e ~ f) ∧ g"
+	node, err := parser.Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := visitor.NewBooleanSolver(visitor.NewEvaluationContext()).Solve(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, filter := range []visitor.RowFilter{visitor.AllRows, visitor.TrueRows, visitor.FalseRows} {
+		for _, pageSize := range []int{1, 10, 64, 1000} {
+			want := slices.DeleteFunc(slices.Clone(table), func(entry visitor.TruthTableEntry) bool {
+				return filter == visitor.TrueRows && !entry.Result || filter == visitor.FalseRows && entry.Result
+			})
+
+			chunks := collect(t, &Logicka{}, input, pageSize, filter)
+			var rows []visitor.TruthTableEntry
+			for i, chunk := range chunks {
+				if chunk.Error != "" {
+					t.Fatalf("%s/%d: chunk %d failed: %s", filter, pageSize, i, chunk.Error)
+				}
+				if chunk.Last != (i == len(chunks)-1) {
+					t.Errorf("%s/%d: chunk %d of %d has Last = %v", filter, pageSize, i, len(chunks), chunk.Last)
+				}
+				if !chunk.Last && len(chunk.Page.Rows) != pageSize {
+					t.Errorf("%s/%d: chunk %d has %d rows", filter, pageSize, i, len(chunk.Page.Rows))
+				}
+				rows = append(rows, chunk.Page.Rows...)
+			}
+
+			if len(rows) != len(want) {
+				t.Fatalf("%s/%d: streamed %d rows, want %d", filter, pageSize, len(rows), len(want))
+			}
+			for i := range want {
+				if rows[i].Minterm != want[i].Minterm || rows[i].Result != want[i].Result || !slices.Equal(rows[i].Variables, want[i].Variables) {
+					t.Fatalf("%s/%d: row %d = %+v, want %+v", filter, pageSize, i, rows[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestStreamRowsError(t *testing.T) {
+	chunks := collect(t, &Logicka{}, "P(a) ∧ b", 10, visitor.AllRows)
+	if len(chunks) != 1 || !chunks[0].Last || chunks[0].Error == "" || chunks[0].Page != nil {
+		t.Fatalf("chunks = %+v, want a single last chunk with an error", chunks)
+	}
+}
+
+func TestStreamRowsCancelled(t *testing.T) {
+	l := &Logicka{}
+	node, err := parser.Parse("a ∧ b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := l.stream.Add(1)
+	l.CancelTruthTableStream()
+	l.streamRows(id, node, visitor.NewEvaluationContext(), 1, visitor.AllRows, func(chunk TruthTableChunk) {
+		t.Errorf("cancelled stream emitted %+v", chunk)
+	})
+}
+
+func TestStreamTruthTableWithoutContext(t *testing.T) {
+	if _, err := (&Logicka{}).StreamTruthTable("a", nil, nil, 10, visitor.AllRows); err == nil {
+		t.Error("StreamTruthTable without the application context succeeded")
+	}
+}
@@ -46,22 +46,132 @@ func (s *Set[T]) List() []T {
 	return list
 }
 
-// UnionSet TODO: implement DSU in future for efficient unions
+// NewSetFrom returns a set holding the given values.
+func NewSetFrom[T comparable](values ...T) *Set[T] {
+	set := &Set[T]{elements: make(map[T]struct{}, len(values)), lock: &sync.RWMutex{}}
+	for _, v := range values {
+		set.elements[v] = struct{}{}
+	}
+	return set
+}
+
+// Clone returns a copy of the set that does not share storage with it.
+func (s *Set[T]) Clone() *Set[T] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	set := &Set[T]{elements: make(map[T]struct{}, len(s.elements)), lock: &sync.RWMutex{}}
+	for v := range s.elements {
+		set.elements[v] = struct{}{}
+	}
+	return set
+}
+
+// IsSubset reports whether every element of s is also in other.
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s == other {
+		return true
+	}
+	elements := s.List()
+	other.lock.RLock()
+	defer other.lock.RUnlock()
+	if len(elements) > len(other.elements) {
+		return false
+	}
+	for _, v := range elements {
+		if _, ok := other.elements[v]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether both sets hold the same elements.
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// The operations below never modify their arguments, so they are safe to use
+// on sets shared between goroutines. They hold the lock of one argument at a
+// time: the other is copied under its own lock first, so two operations that
+// take the same sets in opposite order cannot deadlock. Each one runs in time
+// linear in the size of its result's upper bound.
+
+// UnionSet returns the elements that are in a or in b. It copies the larger
+// set and adds the smaller one to it.
 func UnionSet[T comparable](a, b *Set[T]) *Set[T] {
-	set := NewSet[T]()
-	var first, second *Set[T]
+	if a.Len() < b.Len() {
+		a, b = b, a
+	}
+	set := a.Clone()
+	if a == b {
+		return set
+	}
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for v := range b.elements {
+		set.elements[v] = struct{}{}
+	}
+	return set
+}
+
+// IntersectSet returns the elements that are in both a and b. It walks the
+// smaller set and looks its elements up in the larger one.
+func IntersectSet[T comparable](a, b *Set[T]) *Set[T] {
+	if a == b {
+		return a.Clone()
+	}
 	if a.Len() > b.Len() {
-		first = a
-		second = b
-	} else {
-		first = b
-		second = a
-	}
-	for v1 := range first.elements {
-		for v2 := range second.elements {
-			if v1 == v2 {
-				set.Add(v1)
-			}
+		a, b = b, a
+	}
+	elements := a.List()
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	set := NewSet[T]()
+	for _, v := range elements {
+		if _, ok := b.elements[v]; ok {
+			set.elements[v] = struct{}{}
+		}
+	}
+	return set
+}
+
+// DifferenceSet returns the elements of a that are not in b.
+func DifferenceSet[T comparable](a, b *Set[T]) *Set[T] {
+	if a == b {
+		return NewSet[T]()
+	}
+	elements := a.List()
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	set := NewSet[T]()
+	for _, v := range elements {
+		if _, ok := b.elements[v]; !ok {
+			set.elements[v] = struct{}{}
+		}
+	}
+	return set
+}
+
+// SymmetricDifferenceSet returns the elements that are in exactly one of a and b.
+func SymmetricDifferenceSet[T comparable](a, b *Set[T]) *Set[T] {
+	if a == b {
+		return NewSet[T]()
+	}
+	a = a.Clone()
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	set := NewSet[T]()
+	for v := range a.elements {
+		if _, ok := b.elements[v]; !ok {
+			set.elements[v] = struct{}{}
+		}
+	}
+	for v := range b.elements {
+		if _, ok := a.elements[v]; !ok {
+			set.elements[v] = struct{}{}
 		}
 	}
 	return set
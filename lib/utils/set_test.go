@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func sorted(s *Set[int]) []int {
+	list := s.List()
+	slices.Sort(list)
+	return list
+}
+
+func TestSetOperations(t *testing.T) {
+	a, b := NewSetFrom(1, 2, 3, 4), NewSetFrom(3, 4, 5)
+	tests := []struct {
+		name string
+		got  *Set[int]
+		want []int
+	}{
+		{"union", UnionSet(a, b), []int{1, 2, 3, 4, 5}},
+		{"intersection", IntersectSet(a, b), []int{3, 4}},
+		{"difference", DifferenceSet(a, b), []int{1, 2}},
+		{"reverse difference", DifferenceSet(b, a), []int{5}},
+		{"symmetric difference", SymmetricDifferenceSet(a, b), []int{1, 2, 5}},
+		{"self intersection", IntersectSet(a, a), []int{1, 2, 3, 4}},
+		{"self difference", DifferenceSet(a, a), []int{}},
+	}
+	for _, tt := range tests {
+		if got := sorted(tt.got); !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if !NewSetFrom(3, 4).IsSubset(a) || a.IsSubset(b) {
+		t.Error("IsSubset is wrong")
+	}
+	if !a.Equal(NewSetFrom(4, 3, 2, 1)) || a.Equal(b) {
+		t.Error("Equal is wrong")
+	}
+}
+
+// TestSetOperationsLockOneSet checks that an operation waiting for the lock
+// of one set does not hold the lock of the other. If it did, two operations
+// taking the same sets in opposite order could wait for each other behind
+// pending writers.
+func TestSetOperationsLockOneSet(t *testing.T) {
+	operations := map[string]func(a, b *Set[int]){
+		"IsSubset":               func(a, b *Set[int]) { a.IsSubset(b) },
+		"UnionSet":               func(a, b *Set[int]) { UnionSet(a, b) },
+		"IntersectSet":           func(a, b *Set[int]) { IntersectSet(a, b) },
+		"DifferenceSet":          func(a, b *Set[int]) { DifferenceSet(a, b) },
+		"SymmetricDifferenceSet": func(a, b *Set[int]) { SymmetricDifferenceSet(a, b) },
+	}
+
+	for name, operation := range operations {
+		// a is the smaller set, which IntersectSet walks.
+		a, b := NewSetFrom(1), NewSetFrom(1, 2)
+		b.lock.Lock()
+		done := make(chan struct{})
+		go func() {
+			operation(a, b)
+			close(done)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		if a.lock.TryLock() {
+			a.lock.Unlock()
+		} else {
+			t.Errorf("%s holds the lock of one set while waiting for the other", name)
+		}
+		b.lock.Unlock()
+		<-done
+	}
+}
@@ -0,0 +1,90 @@
+package visitor
+
+// bitVector holds one truth value per row of a truth table, row r in bit
+// r%64 of word r/64. Bits past the last row are always zero.
+type bitVector []uint64
+
+// words returns the number of words a vector of the given rows takes.
+func words(rows int) int {
+	return (rows + 63) / 64
+}
+
+// constantVector returns the vector that is value on every row.
+func constantVector(rows int, value bool) bitVector {
+	v := make(bitVector, words(rows))
+	if value {
+		for i := range v {
+			v[i] = ^uint64(0)
+		}
+		v.clearTail(rows)
+	}
+	return v
+}
+
+// variableVector returns the column of the variable at position index among
+// count variables.
+func variableVector(rows, count, index int) bitVector {
+	v := make(bitVector, words(rows))
+	for w := range v {
+		v[w] = variableWord(count, index, w)
+	}
+	v.clearTail(rows)
+	return v
+}
+
+// variableWord returns word w of the column of the variable at position index
+// among count variables. Rows count in binary with the first variable as the
+// most significant bit, so the variable is true on row r when bit
+// count-1-index of r is set.
+func variableWord(count, index, w int) uint64 {
+	shift := count - 1 - index
+	if shift >= 6 {
+		// Whole words alternate between runs of zeros and runs of ones.
+		if w>>(shift-6)&1 == 1 {
+			return ^uint64(0)
+		}
+		return 0
+	}
+	return patterns[shift]
+}
+
+// patterns are the words of the last six variables, which repeat within a
+// word.
+var patterns = [6]uint64{
+	0xAAAAAAAAAAAAAAAA,
+	0xCCCCCCCCCCCCCCCC,
+	0xF0F0F0F0F0F0F0F0,
+	0xFF00FF00FF00FF00,
+	0xFFFF0000FFFF0000,
+	0xFFFFFFFF00000000,
+}
+
+// clearTail zeroes the bits past the last row.
+func (v bitVector) clearTail(rows int) {
+	if rem := rows % 64; rem != 0 && len(v) > 0 {
+		v[len(v)-1] &= 1<<rem - 1
+	}
+}
+
+func (v bitVector) get(row int) bool {
+	return v[row/64]>>(row%64)&1 == 1
+}
+
+// combine returns the vector of op applied row by row to a and b.
+func combine(a, b bitVector, rows int, op func(x, y uint64) uint64) bitVector {
+	v := make(bitVector, len(a))
+	for i := range v {
+		v[i] = op(a[i], b[i])
+	}
+	v.clearTail(rows)
+	return v
+}
+
+func (v bitVector) not(rows int) bitVector {
+	result := make(bitVector, len(v))
+	for i := range v {
+		result[i] = ^v[i]
+	}
+	result.clearTail(rows)
+	return result
+}
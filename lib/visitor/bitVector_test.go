@@ -0,0 +1,157 @@
+package visitor_test
+
+import (
+	"errors"
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/parser"
+	"logicka/lib/visitor"
+	"strings"
+	"testing"
+)
+
+// reference evaluates a propositional formula directly, one assignment at a
+// time.
+func reference(t *testing.T, node ast.ASTNode, values map[string]bool) bool {
+	t.Helper()
+	switch n := node.(type) {
+	case *ast.LiteralNode:
+		return n.Value
+	case *ast.VariableNode:
+		return values[n.Name]
+	case *ast.GroupingNode:
+		return reference(t, n.Expr, values)
+	case *ast.UnaryNode:
+		return !reference(t, n.Operand, values)
+	case *ast.BinaryNode:
+		x, y := reference(t, n.Left, values), reference(t, n.Right, values)
+		switch n.Operator {
+		case lexer.IMPL:
+			return !x || y
+		case lexer.CONV:
+			return x || !y
+		case lexer.EQUIV:
+			return x == y
+		case lexer.CONJ:
+			return x && y
+		case lexer.DISJ:
+			return x || y
+		case lexer.XOR:
+			return x != y
+		case lexer.NAND:
+			return !(x && y)
+		case lexer.NOR:
+			return !(x || y)
+		}
+	case *ast.ChainNode:
+		result := n.Operator == lexer.CONJ
+		for _, operand := range n.Operands {
+			if n.Operator == lexer.CONJ {
+				result = result && reference(t, operand, values)
+			} else {
+				result = result || reference(t, operand, values)
+			}
+		}
+		return result
+	}
+	t.Fatalf("reference cannot evaluate %T", node)
+	return false
+}
+
+func TestSolveBitVectors(t *testing.T) {
+	tests := []string{
+		"a",
+		"!a",
+		"1 ∧ a",
+		"a → b",
+		"a ← b",
+		"a ↔ b",
+		"a ∧ b",
+		"a ∨ b",
+		"a ⊕ b",
+		"a ↑ b",
+		"a ↓ b",
+		"(a → b) ∧ (b ← c) ∨ !(d ⊕ e)",
+		// Seven variables fill two words, the first of them a whole one.
+		"(a ↑ b) ↓ (c ⊕ d) ∨ (e ↔ f) ∧ !g",
+		"(a ∧ b ∧ c) ∨ (d ∧ e ∧ f) ∨ (g ∧ h)",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			node, err := parser.Parse(input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", input, err)
+			}
+			checkSolve(t, node)
+		})
+	}
+}
+
+func TestSolveBitVectorsChain(t *testing.T) {
+	a, b, c := ast.NewVariableNode("a"), ast.NewVariableNode("b"), ast.NewVariableNode("c")
+	for _, operator := range []lexer.BooleanTokenType{lexer.CONJ, lexer.DISJ} {
+		chain, err := ast.NewChainNode(operator, a, ast.NewUnaryNode(lexer.NEG, b), c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(chain.String(), func(t *testing.T) {
+			checkSolve(t, chain)
+		})
+	}
+}
+
+// checkSolve compares the table Solve computes for node with the reference
+// evaluator, row by row.
+func checkSolve(t *testing.T, node ast.ASTNode) {
+	t.Helper()
+	table, err := visitor.NewBooleanSolver(visitor.NewEvaluationContext()).Solve(node)
+	if err != nil {
+		t.Fatalf("Solve(%s): %v", node, err)
+	}
+	count := len(visitor.PropositionalVariables(node))
+	if len(table) != 1<<count {
+		t.Fatalf("Solve(%s) has %d rows, want %d", node, len(table), 1<<count)
+	}
+
+	for row, entry := range table {
+		values := make(map[string]bool, len(entry.Variables))
+		for i, variable := range entry.Variables {
+			if want := row>>(count-1-i)&1 == 1; variable.Value != want {
+				t.Fatalf("row %d: %s = %v, want %v", row, variable.Name, variable.Value, want)
+			}
+			values[variable.Name] = variable.Value
+		}
+		if want := reference(t, node, values); entry.Result != want {
+			t.Errorf("row %d %v: %s = %v, want %v", row, values, node, entry.Result, want)
+		}
+	}
+}
+
+func TestSolveTooManyVariables(t *testing.T) {
+	names := make([]string, visitor.MaxSolveVariables+1)
+	for i := range names {
+		names[i] = fmt.Sprintf("v%d", i+10)
+	}
+	node, err := parser.Parse(strings.Join(names, " ∧ "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = visitor.NewBooleanSolver(visitor.NewEvaluationContext()).Solve(node)
+	var tooMany visitor.TooManyVariablesError
+	if !errors.As(err, &tooMany) || tooMany.Max != visitor.MaxSolveVariables {
+		t.Fatalf("Solve of %d variables = %v, want a TooManyVariablesError", len(names), err)
+	}
+
+	// Tables below the cap are returned whole.
+	node, err = parser.Parse(strings.Join(names[:17], " ∧ "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := visitor.NewBooleanSolver(visitor.NewEvaluationContext()).Solve(node)
+	if err != nil || len(table) != 1<<17 || !table[len(table)-1].Result {
+		t.Fatalf("Solve of 17 variables = %d rows, %v, want %d", len(table), err, 1<<17)
+	}
+}
@@ -4,206 +4,544 @@ import (
 	"fmt"
 	"logicka/lib/ast"
 	"logicka/lib/lexer"
+	"maps"
+	"slices"
 )
 
+// MaxTableVariables bounds the number of variables a truth table may
+// enumerate; each subformula takes 2^n bits.
+const MaxTableVariables = 24
+
+// MaxSolveVariables bounds the number of variables of a table Solve returns
+// whole, as every row is a TruthTableEntry of its own: a million rows take
+// hundreds of megabytes, which is as far as it goes below MaxTableVariables.
+// Larger tables are read with SolvePage and SolveRows.
+const MaxSolveVariables = 20
+
+// TooManyVariablesError is returned for a formula with more free variables
+// than a table can enumerate, Max.
+type TooManyVariablesError struct {
+	Variables []string
+	Max       int
+}
+
+func (e TooManyVariablesError) Error() string {
+	return fmt.Sprintf("a truth table of %d variables has too many rows, at most %d variables are supported", len(e.Variables), e.Max)
+}
+
+// TruthTableEntry is a row of a truth table. Minterm is the number of the
+// row's assignment to Variables read as a binary number, the first variable
+// being the most significant bit; it equals the position of the row when no
+// variable is fixed.
 type TruthTableEntry struct {
-	Result    bool
-	Variables []TruthTableVariable
+	Minterm     int
+	Result      bool
+	Variables   []TruthTableVariable
+	Witnesses   []QuantifierWitness
+	Subformulas []SubformulaValue // filled by SolveSteps only
 }
 
 type TruthTableVariable struct {
 	Name  string
 	Value bool
 }
+
+// QuantifierWitness explains the value of a quantified subformula on one row
+// of the table. Element is the witness of a true ∃ or the counterexample to a
+// false ∀; it is empty when the value was reached on every element of the
+// domain. Bindings holds the elements bound by the enclosing quantifiers.
+type QuantifierWitness struct {
+	Formula  string
+	Variable string
+	Result   bool
+	Element  string
+	Bindings map[string]string
+}
+
+// BooleanSolver computes truth tables. The variables of the formula are put
+// in alphabetical order, or in the order of the context, and those it does
+// not fix are enumerated in binary counting order: row r assigns true to the
+// i-th of n variables when bit n-1-i of r is set, so the first row makes
+// every variable false and the first variable changes slowest. Every
+// subformula is evaluated once, to a bit vector holding its value on all
+// rows.
 type BooleanSolver struct {
-	context *EvaluationContext
+	context   *EvaluationContext
+	bindings  map[string]string // elements bound to term variables by enclosing quantifiers
+	variables []string          // the enumerated variables, in table order
+	listed    []string          // the variables a row lists, enumerated or fixed
+	shifts    []int             // the bit of the row number holding each listed variable, -1 if fixed
+	rows      int
+	columns   map[string]bitVector // columns of the enumerated variables
+	whole     *wholeTable          // the table scanWhole evaluated last
+	cancelled func() bool          // stops SolvePage and SolveRows, see SetCancel
+}
+
+// column is the value of a subformula on every row of the table. witnesses
+// explains the quantifiers of the subformula on a row; it is nil when there
+// are none.
+type column struct {
+	values    bitVector
+	witnesses func(row int) []QuantifierWitness
 }
 
 func NewBooleanSolver(context *EvaluationContext) *BooleanSolver {
-	return &BooleanSolver{context: context}
+	return &BooleanSolver{context: context, bindings: make(map[string]string)}
 }
 
 func (s *BooleanSolver) Solve(node ast.ASTNode) ([]TruthTableEntry, error) {
-	return Accept[[]TruthTableEntry](node, s)
+	result, err := s.table(node, MaxSolveVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	table := make([]TruthTableEntry, s.rows)
+	for row := range s.rows {
+		table[row] = s.explainedEntry(row, result)
+	}
+	return table, nil
 }
 
-func (s *BooleanSolver) VisitGrouping(node *ast.GroupingNode) ([]TruthTableEntry, error) {
-	return Accept[[]TruthTableEntry](node.Expr, s)
+// table lays out the table of node, which may enumerate at most max
+// variables, and returns the column of node.
+func (s *BooleanSolver) table(node ast.ASTNode, max int) (column, error) {
+	if err := s.layout(node, max); err != nil {
+		return column{}, err
+	}
+	s.columns = make(map[string]bitVector, len(s.variables))
+	return s.evaluate(node)
 }
 
-func (s *BooleanSolver) VisitLiteral(node *ast.LiteralNode) ([]TruthTableEntry, error) {
-	return []TruthTableEntry{
-		{Result: node.Value, Variables: []TruthTableVariable{}},
-	}, nil
+// layout fixes the rows of the table of node: the variables the context
+// does not fix are enumerated, and there may be at most max of them.
+func (s *BooleanSolver) layout(node ast.ASTNode, max int) error {
+	s.listed = orderVariables(tableVariables(node), s.context.Order)
+	s.variables = nil
+	for _, name := range s.listed {
+		if _, ok := s.context.Variables[name]; !ok {
+			s.variables = append(s.variables, name)
+		}
+	}
+	if len(s.variables) > max {
+		return TooManyVariablesError{Variables: s.variables, Max: max}
+	}
+	s.rows = 1 << len(s.variables)
+
+	s.shifts = make([]int, len(s.listed))
+	for i, name := range s.listed {
+		s.shifts[i] = -1
+		if index := slices.Index(s.variables, name); index >= 0 {
+			s.shifts[i] = len(s.variables) - 1 - index
+		}
+	}
+	return nil
 }
 
-func (s *BooleanSolver) VisitVariable(node *ast.VariableNode) ([]TruthTableEntry, error) {
-	if val, ok := s.context.Variables[node.Name]; ok {
-		return []TruthTableEntry{
-			{Result: val, Variables: []TruthTableVariable{
-				{Name: node.Name, Value: val},
-			}},
-		}, nil
+// entry returns the row of the table with the given number and result.
+func (s *BooleanSolver) entry(row int, result bool) TruthTableEntry {
+	variables := make([]TruthTableVariable, len(s.listed))
+	minterm := 0
+	for i, name := range s.listed {
+		value := s.context.Variables[name]
+		if s.shifts[i] >= 0 {
+			value = row>>s.shifts[i]&1 == 1
+		}
+		variables[i] = TruthTableVariable{Name: name, Value: value}
+		minterm = minterm<<1 | int(boolWord(value))
 	}
-	return []TruthTableEntry{
-		{Result: true, Variables: []TruthTableVariable{
-			{Name: node.Name, Value: true},
-		}},
-		{Result: false, Variables: []TruthTableVariable{
-			{Name: node.Name, Value: false},
-		}},
-	}, nil
+	return TruthTableEntry{Minterm: minterm, Result: result, Variables: variables}
 }
 
-func (s *BooleanSolver) VisitBinary(node *ast.BinaryNode) ([]TruthTableEntry, error) {
-	left, err := Accept[[]TruthTableEntry](node.Left, s)
-	if err != nil {
-		return nil, err
+// explainedEntry returns the row of the table with the given number, with
+// the witnesses of result on it.
+func (s *BooleanSolver) explainedEntry(row int, result column) TruthTableEntry {
+	entry := s.entry(row, result.values.get(row))
+	entry.Witnesses = result.explain(row)
+	return entry
+}
+
+// orderVariables puts the variables named by order first, in that order,
+// followed by the others in their order in variables. Names of order that
+// are not among variables are skipped.
+func orderVariables(variables, order []string) []string {
+	ordered := make([]string, 0, len(variables))
+	for _, name := range order {
+		if slices.Contains(variables, name) && !slices.Contains(ordered, name) {
+			ordered = append(ordered, name)
+		}
 	}
-	right, err := Accept[[]TruthTableEntry](node.Right, s)
-	if err != nil {
-		return nil, err
+	for _, name := range variables {
+		if !slices.Contains(ordered, name) {
+			ordered = append(ordered, name)
+		}
+	}
+	return ordered
+}
+
+// evaluate returns the column of node. Propositional formulas are compiled
+// and evaluated a word of rows at a time; the others are evaluated
+// subformula by subformula.
+func (s *BooleanSolver) evaluate(node ast.ASTNode) (column, error) {
+	if compiled, err := Compile(node); err == nil {
+		return s.evaluateCompiled(compiled), nil
+	}
+	return Accept[column](node, s)
+}
+
+// evaluateCompiled evaluates a compiled formula on every row of the table.
+func (s *BooleanSolver) evaluateCompiled(compiled *Compiled) column {
+	inputs := make([]bitVector, len(compiled.Variables))
+	for i, name := range compiled.Variables {
+		if value, ok := s.context.Variables[name]; ok {
+			inputs[i] = constantVector(s.rows, value)
+		} else {
+			inputs[i] = variableVector(s.rows, len(s.variables), slices.Index(s.variables, name))
+		}
+	}
+
+	values := make(bitVector, words(s.rows))
+	assignment := make([]uint64, len(inputs))
+	for w := range values {
+		for i, input := range inputs {
+			assignment[i] = input[w]
+		}
+		values[w] = compiled.EvalWords(assignment)
 	}
+	values.clearTail(s.rows)
+	return column{values: values}
+}
+
+// tableVariables returns the propositional variables of node in
+// alphabetical order.
+func tableVariables(node ast.ASTNode) []string {
+	variables := PropositionalVariables(node)
+	slices.Sort(variables)
+	return variables
+}
 
-	res := make([]TruthTableEntry, 0)
+// PropositionalVariables returns the distinct propositional variables of
+// node in order of first appearance. Arguments of predicates are terms, and
+// variables bound by a quantifier over truth values take both values inside
+// it, so neither are among them.
+func PropositionalVariables(node ast.ASTNode) []string {
+	var variables []string
 
-	for _, l := range left {
-		for _, r := range right {
-			merged := mergeVariables(l.Variables, r.Variables)
-			if merged == nil {
-				continue
+	var walk func(node ast.ASTNode, bound []string)
+	walk = func(node ast.ASTNode, bound []string) {
+		switch n := node.(type) {
+		case *ast.VariableNode:
+			if !slices.Contains(variables, n.Name) && !slices.Contains(bound, n.Name) {
+				variables = append(variables, n.Name)
+			}
+		case *ast.PredicateNode:
+			return
+		case *ast.QuantifierNode:
+			if n.Propositional() {
+				bound = append(slices.Clone(bound), n.Variable)
 			}
-			switch op := node.Operator; op {
-			case lexer.IMPL:
-				res = append(res, TruthTableEntry{
-					Result:    !l.Result || r.Result,
-					Variables: merged,
-				})
-			case lexer.EQUIV:
-				res = append(res, TruthTableEntry{
-					Result:    l.Result == r.Result,
-					Variables: merged,
-				})
-			case lexer.CONJ:
-				res = append(res, TruthTableEntry{
-					Result:    l.Result && r.Result,
-					Variables: merged,
-				})
-			case lexer.DISJ:
-				res = append(res, TruthTableEntry{
-					Result:    l.Result || r.Result,
-					Variables: merged,
-				})
-			default:
-				return nil, fmt.Errorf("unkown operator: %s", op)
+			walk(n.Body, bound)
+		case ast.Traversable:
+			for _, child := range n.Children() {
+				walk(child, bound)
 			}
 		}
 	}
+	walk(node, nil)
 
-	return res, nil
+	return variables
 }
 
-func (s *BooleanSolver) VisitChain(node *ast.ChainNode) ([]TruthTableEntry, error) {
-	if len(node.Operands) < 2 {
-		return nil, fmt.Errorf("chain must have at least 2 operands, got %d", len(node.Operands))
+func (s *BooleanSolver) constant(value bool) column {
+	return column{values: constantVector(s.rows, value)}
+}
+
+func (s *BooleanSolver) VisitGrouping(node *ast.GroupingNode) (column, error) {
+	return Accept[column](node.Expr, s)
+}
+
+func (s *BooleanSolver) VisitLiteral(node *ast.LiteralNode) (column, error) {
+	return s.constant(node.Value), nil
+}
+
+func (s *BooleanSolver) VisitVariable(node *ast.VariableNode) (column, error) {
+	if value, ok := s.context.Variables[node.Name]; ok {
+		return s.constant(value), nil
+	}
+
+	values, ok := s.columns[node.Name]
+	if !ok {
+		index := slices.Index(s.variables, node.Name)
+		if index < 0 {
+			return column{}, UnboundVariableError{Name: node.Name, Span: node.Span()}
+		}
+		values = variableVector(s.rows, len(s.variables), index)
+		s.columns[node.Name] = values
 	}
+	return column{values: values}, nil
+}
 
-	result, err := Accept[[]TruthTableEntry](node.Operands[0], s)
+func (s *BooleanSolver) VisitBinary(node *ast.BinaryNode) (column, error) {
+	left, err := Accept[column](node.Left, s)
 	if err != nil {
-		return nil, err
+		return column{}, err
+	}
+	right, err := Accept[column](node.Right, s)
+	if err != nil {
+		return column{}, err
+	}
+	return s.binary(node, left, right)
+}
+
+// binary combines the columns of the operands of node.
+func (s *BooleanSolver) binary(node *ast.BinaryNode, left, right column) (column, error) {
+	op, ok := operations[node.Operator]
+	if !ok {
+		return column{}, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+
+	return column{
+		values:    combine(left.values, right.values, s.rows, op.word),
+		witnesses: concatWitnesses(left.witnesses, right.witnesses),
+	}, nil
+}
+
+func (s *BooleanSolver) VisitChain(node *ast.ChainNode) (column, error) {
+	if len(node.Operands) < 2 {
+		return column{}, fmt.Errorf("chain must have at least 2 operands, got %d", len(node.Operands))
 	}
 
-	for _, operand := range node.Operands[1:] {
-		operandEntries, err := Accept[[]TruthTableEntry](operand, s)
+	operands := make([]column, len(node.Operands))
+	for i, operand := range node.Operands {
+		c, err := Accept[column](operand, s)
 		if err != nil {
-			return nil, err
-		}
-
-		newResult := make([]TruthTableEntry, 0)
-
-		for _, leftEntry := range result {
-			for _, rightEntry := range operandEntries {
-				merged := mergeVariables(leftEntry.Variables, rightEntry.Variables)
-				if merged == nil {
-					continue
-				}
-
-				var combinedResult bool
-				switch node.Operator {
-				case lexer.CONJ:
-					combinedResult = leftEntry.Result && rightEntry.Result
-				case lexer.DISJ:
-					combinedResult = leftEntry.Result || rightEntry.Result
-				default:
-					return nil, fmt.Errorf("unsupported chain operator: %s", node.Operator)
-				}
-
-				newResult = append(newResult, TruthTableEntry{
-					Result:    combinedResult,
-					Variables: merged,
-				})
-			}
+			return column{}, err
 		}
+		operands[i] = c
+	}
+	return s.chain(node, operands)
+}
 
-		result = newResult
+// chain combines the columns of the operands of node.
+func (s *BooleanSolver) chain(node *ast.ChainNode, operands []column) (column, error) {
+	if node.Operator != lexer.CONJ && node.Operator != lexer.DISJ {
+		return column{}, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
 	}
+	op := operations[node.Operator]
 
+	result := operands[0]
+	for _, next := range operands[1:] {
+		result = column{
+			values:    combine(result.values, next.values, s.rows, op.word),
+			witnesses: concatWitnesses(result.witnesses, next.witnesses),
+		}
+	}
 	return result, nil
 }
 
-func (s *BooleanSolver) VisitUnary(node *ast.UnaryNode) ([]TruthTableEntry, error) {
-	operands, err := Accept[[]TruthTableEntry](node.Operand, s)
+func (s *BooleanSolver) VisitUnary(node *ast.UnaryNode) (column, error) {
+	operand, err := Accept[column](node.Operand, s)
 	if err != nil {
-		return nil, err
+		return column{}, err
+	}
+	return s.unary(node, operand)
+}
+
+// unary applies the operator of node to the column of its operand.
+func (s *BooleanSolver) unary(node *ast.UnaryNode, operand column) (column, error) {
+	switch op := node.Operator; op {
+	case lexer.NEG:
+		return column{values: operand.values.not(s.rows), witnesses: operand.witnesses}, nil
+	default:
+		return column{}, OperatorError{Operator: op.String(), Span: node.Span()}
 	}
+}
 
-	res := make([]TruthTableEntry, 0)
+func (s *BooleanSolver) VisitPredicate(node *ast.PredicateNode) (column, error) {
+	if s.context.Structure == nil {
+		return column{}, StructureError{Message: fmt.Sprintf("predicate %s needs a structure to be evaluated", node.Name)}
+	}
 
-	for _, o := range operands {
-		switch op := node.Operator; op {
-		case lexer.NEG:
-			res = append(res, TruthTableEntry{
-				Result:    !o.Result,
-				Variables: o.Variables,
-			})
-		default:
-			return nil, fmt.Errorf("unkown operator: %s", op)
+	args := make([]string, len(node.Args))
+	for i, arg := range node.Args {
+		element, err := s.evaluateTerm(arg)
+		if err != nil {
+			return column{}, err
 		}
+		args[i] = element
+	}
+
+	holds, err := s.context.Structure.holds(node.Name, args)
+	if err != nil {
+		return column{}, err
+	}
+	return s.constant(holds), nil
+}
+
+// VisitQuantifier evaluates the body once for every element the quantifier
+// ranges over and combines the results row by row. A quantifier over truth
+// values evaluates it once for true and once for false instead and needs no
+// structure.
+func (s *BooleanSolver) VisitQuantifier(node *ast.QuantifierNode) (column, error) {
+	if node.Propositional() && (s.context.Structure == nil || slices.Contains(ast.FreeVariables(node.Body), node.Variable)) {
+		return s.quantify(node, []string{"true", "false"}, false, s.bindTruthValue)
+	}
+
+	if s.context.Structure == nil {
+		return column{}, StructureError{Message: fmt.Sprintf("quantifier %s needs a structure to be evaluated", node.String())}
+	}
+	if len(s.context.Structure.Domain) == 0 {
+		return column{}, StructureError{Message: "the domain must not be empty"}
+	}
+	domain, err := s.context.Structure.elements(node)
+	if err != nil {
+		return column{}, err
 	}
 
-	return res, nil
+	// A quantifier over an empty set is vacuously true or false. The body is
+	// still evaluated once, on any element, to report its errors.
+	vacuous := len(domain) == 0
+	if vacuous {
+		domain = s.context.Structure.Domain[:1]
+	}
+	return s.quantify(node, domain, vacuous, s.bindElement)
 }
 
-func (s *BooleanSolver) VisitPredicate(node *ast.PredicateNode) ([]TruthTableEntry, error) {
-	// TODO implement me
-	panic("implement me")
+// bindElement binds variable to a domain element and returns a function
+// that undoes it.
+func (s *BooleanSolver) bindElement(variable, element string) func() {
+	outer := s.bindings
+	s.bindings = maps.Clone(outer)
+	s.bindings[variable] = element
+	return func() { s.bindings = outer }
 }
 
-func (s *BooleanSolver) VisitQuantifier(node *ast.QuantifierNode) ([]TruthTableEntry, error) {
-	// TODO implement me
-	panic("implement me")
+// bindTruthValue fixes the propositional variable to the truth value value
+// names and returns a function that undoes it. The value is also kept with
+// the bindings, so the witnesses of inner quantifiers show it.
+func (s *BooleanSolver) bindTruthValue(variable, value string) func() {
+	outer := s.context
+	variables := maps.Clone(outer.Variables)
+	if variables == nil {
+		variables = make(map[string]bool)
+	}
+	variables[variable] = value == "true"
+	s.context = &EvaluationContext{Variables: variables, Structure: outer.Structure, Order: outer.Order}
+
+	restore := s.bindElement(variable, value)
+	return func() {
+		restore()
+		s.context = outer
+	}
 }
 
-func mergeVariables(left, right []TruthTableVariable) []TruthTableVariable {
-	varMap := make(map[string]bool)
+// quantify evaluates the body of node for each of values in turn, bound by
+// bind, and combines the columns: ∀ is true on the rows where every column
+// is, ∃ on the rows where some column is.
+func (s *BooleanSolver) quantify(node *ast.QuantifierNode, values []string, vacuous bool, bind func(variable, value string) func()) (column, error) {
+	forall := node.Type == lexer.FORALL
+	bindings := maps.Clone(s.bindings)
 
-	for _, v := range left {
-		varMap[v.Name] = v.Value
+	perValue := make([]column, len(values))
+	for i, value := range values {
+		restore := bind(node.Variable, value)
+		body, err := Accept[column](node.Body, s)
+		restore()
+		if err != nil {
+			return column{}, err
+		}
+		perValue[i] = body
 	}
 
-	for _, v := range right {
-		if val, ok := varMap[v.Name]; ok {
-			if val != v.Value {
-				return nil
-			}
+	if vacuous {
+		witness := QuantifierWitness{Formula: node.String(), Variable: node.Variable, Result: forall, Bindings: bindings}
+		return column{
+			values:    constantVector(s.rows, forall),
+			witnesses: func(int) []QuantifierWitness { return []QuantifierWitness{witness} },
+		}, nil
+	}
+
+	result := perValue[0].values
+	for _, body := range perValue[1:] {
+		if forall {
+			result = combine(result, body.values, s.rows, operations[lexer.CONJ].word)
 		} else {
-			varMap[v.Name] = v.Value
+			result = combine(result, body.values, s.rows, operations[lexer.DISJ].word)
+		}
+	}
+
+	// The deciding value on a row is the first counterexample of ∀ or the
+	// first witness of ∃, or every value when there is none.
+	witnesses := func(row int) []QuantifierWitness {
+		witness := QuantifierWitness{
+			Formula:  node.String(),
+			Variable: node.Variable,
+			Result:   result.get(row),
+			Bindings: bindings,
+		}
+		for i, body := range perValue {
+			if body.values.get(row) != forall {
+				witness.Element = values[i]
+				return append([]QuantifierWitness{witness}, body.explain(row)...)
+			}
+		}
+		explanation := []QuantifierWitness{witness}
+		for _, body := range perValue {
+			explanation = append(explanation, body.explain(row)...)
 		}
+		return explanation
 	}
+	return column{values: result, witnesses: witnesses}, nil
+}
+
+// explain returns the witnesses of c on row.
+func (c column) explain(row int) []QuantifierWitness {
+	if c.witnesses == nil {
+		return nil
+	}
+	return c.witnesses(row)
+}
+
+// concatWitnesses joins the explanations of two operands.
+func concatWitnesses(a, b func(row int) []QuantifierWitness) func(row int) []QuantifierWitness {
+	if a == nil && b == nil {
+		return nil
+	}
+	return func(row int) []QuantifierWitness {
+		return slices.Concat(column{witnesses: a}.explain(row), column{witnesses: b}.explain(row))
+	}
+}
 
-	result := make([]TruthTableVariable, 0, len(varMap))
-	for name, value := range varMap {
-		result = append(result, TruthTableVariable{Name: name, Value: value})
+// evaluateTerm returns the domain element a term denotes.
+func (s *BooleanSolver) evaluateTerm(node ast.ASTNode) (string, error) {
+	structure := s.context.Structure
+
+	switch n := node.(type) {
+	case *ast.VariableNode:
+		if element, ok := s.bindings[n.Name]; ok {
+			return element, nil
+		}
+		if element, ok := structure.Constants[n.Name]; ok {
+			return element, nil
+		}
+		if slices.Contains(structure.Domain, n.Name) {
+			return n.Name, nil
+		}
+		return "", UnboundVariableError{Name: n.Name, Span: n.Span()}
+	case *ast.FunctionNode:
+		args := make([]string, len(n.Args))
+		for i, arg := range n.Args {
+			element, err := s.evaluateTerm(arg)
+			if err != nil {
+				return "", err
+			}
+			args[i] = element
+		}
+		return structure.apply(n.Name, args)
+	default:
+		return "", NodeTypeError{NodeType: fmt.Sprintf("%T", n)}
 	}
-	return result
+}
+
+func (s *BooleanSolver) VisitFunction(node *ast.FunctionNode) (column, error) {
+	return column{}, TermError{Term: node.String(), Span: node.Span()}
 }
@@ -0,0 +1,250 @@
+package visitor
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"slices"
+)
+
+// CompileError is returned for a part of a formula that cannot be compiled:
+// only propositional formulas, without predicates and quantifiers, can.
+type CompileError struct {
+	Node string
+	Span lexer.Span
+}
+
+func (e CompileError) Error() string {
+	return fmt.Sprintf("%s at position %d cannot be compiled, only propositional formulas can", e.Node, e.Span.Start)
+}
+
+// operation is a connective evaluated on single truth values and on 64 of
+// them packed in a word.
+type operation struct {
+	scalar func(x, y bool) bool
+	word   func(x, y uint64) uint64
+}
+
+var operations = map[lexer.BooleanTokenType]operation{
+	lexer.IMPL: {
+		scalar: func(x, y bool) bool { return !x || y },
+		word:   func(x, y uint64) uint64 { return ^x | y },
+	},
+	lexer.CONV: {
+		scalar: func(x, y bool) bool { return x || !y },
+		word:   func(x, y uint64) uint64 { return x | ^y },
+	},
+	lexer.EQUIV: {
+		scalar: func(x, y bool) bool { return x == y },
+		word:   func(x, y uint64) uint64 { return ^(x ^ y) },
+	},
+	lexer.CONJ: {
+		scalar: func(x, y bool) bool { return x && y },
+		word:   func(x, y uint64) uint64 { return x & y },
+	},
+	lexer.DISJ: {
+		scalar: func(x, y bool) bool { return x || y },
+		word:   func(x, y uint64) uint64 { return x | y },
+	},
+	lexer.XOR: {
+		scalar: func(x, y bool) bool { return x != y },
+		word:   func(x, y uint64) uint64 { return x ^ y },
+	},
+	lexer.NAND: {
+		scalar: func(x, y bool) bool { return !(x && y) },
+		word:   func(x, y uint64) uint64 { return ^(x & y) },
+	},
+	lexer.NOR: {
+		scalar: func(x, y bool) bool { return !(x || y) },
+		word:   func(x, y uint64) uint64 { return ^(x | y) },
+	},
+}
+
+// Compiled is a propositional formula compiled to a tree of closures, for
+// evaluating it under many assignments without walking the AST again. An
+// assignment gives the value of Variables[i] at index i and must cover all
+// of them.
+type Compiled struct {
+	Variables []string // in alphabetical order
+	scalar    func(assignment []bool) bool
+	word      func(assignment []uint64) uint64
+}
+
+// Compile compiles node, which must not contain predicates or quantifiers.
+func Compile(node ast.ASTNode) (*Compiled, error) {
+	c := &compiler{variables: tableVariables(node)}
+	code, err := Accept[compiledNode](node, c)
+	if err != nil {
+		return nil, err
+	}
+	return &Compiled{Variables: c.variables, scalar: code.scalar, word: code.word}, nil
+}
+
+// Slot returns the index of the variable name in assignments, or -1 when the
+// formula does not contain it.
+func (c *Compiled) Slot(name string) int {
+	return slices.Index(c.Variables, name)
+}
+
+// Eval returns the value of the formula under assignment.
+func (c *Compiled) Eval(assignment []bool) bool {
+	return c.scalar(assignment)
+}
+
+// EvalWords evaluates the formula under 64 assignments at once: bit j of
+// assignment[i] is the value of Variables[i] in the j-th assignment, and bit
+// j of the result is the value of the formula under it.
+func (c *Compiled) EvalWords(assignment []uint64) uint64 {
+	return c.word(assignment)
+}
+
+// EvalBatch returns the value of the formula under each of assignments. The
+// assignments are packed into words and evaluated 64 at a time.
+func (c *Compiled) EvalBatch(assignments [][]bool) []bool {
+	results := make([]bool, len(assignments))
+	words := make([]uint64, len(c.Variables))
+	for start := 0; start < len(assignments); start += 64 {
+		batch := assignments[start:min(start+64, len(assignments))]
+		clear(words)
+		for j, assignment := range batch {
+			for i := range words {
+				if assignment[i] {
+					words[i] |= 1 << j
+				}
+			}
+		}
+
+		values := c.word(words)
+		for j := range batch {
+			results[start+j] = values>>j&1 == 1
+		}
+	}
+	return results
+}
+
+type compiledNode struct {
+	scalar func(assignment []bool) bool
+	word   func(assignment []uint64) uint64
+}
+
+type compiler struct {
+	variables []string
+}
+
+func constantNode(value bool) compiledNode {
+	var word uint64
+	if value {
+		word = ^uint64(0)
+	}
+	return compiledNode{
+		scalar: func([]bool) bool { return value },
+		word:   func([]uint64) uint64 { return word },
+	}
+}
+
+func binaryNode(op operation, left, right compiledNode) compiledNode {
+	return compiledNode{
+		scalar: func(assignment []bool) bool { return op.scalar(left.scalar(assignment), right.scalar(assignment)) },
+		word:   func(assignment []uint64) uint64 { return op.word(left.word(assignment), right.word(assignment)) },
+	}
+}
+
+func (c *compiler) VisitGrouping(node *ast.GroupingNode) (compiledNode, error) {
+	return Accept[compiledNode](node.Expr, c)
+}
+
+func (c *compiler) VisitLiteral(node *ast.LiteralNode) (compiledNode, error) {
+	return constantNode(node.Value), nil
+}
+
+func (c *compiler) VisitVariable(node *ast.VariableNode) (compiledNode, error) {
+	slot := slices.Index(c.variables, node.Name)
+	return compiledNode{
+		scalar: func(assignment []bool) bool { return assignment[slot] },
+		word:   func(assignment []uint64) uint64 { return assignment[slot] },
+	}, nil
+}
+
+func (c *compiler) VisitBinary(node *ast.BinaryNode) (compiledNode, error) {
+	op, ok := operations[node.Operator]
+	if !ok {
+		return compiledNode{}, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+	left, err := Accept[compiledNode](node.Left, c)
+	if err != nil {
+		return compiledNode{}, err
+	}
+	right, err := Accept[compiledNode](node.Right, c)
+	if err != nil {
+		return compiledNode{}, err
+	}
+	return binaryNode(op, left, right), nil
+}
+
+func (c *compiler) VisitChain(node *ast.ChainNode) (compiledNode, error) {
+	if len(node.Operands) < 2 {
+		return compiledNode{}, fmt.Errorf("chain must have at least 2 operands, got %d", len(node.Operands))
+	}
+	if node.Operator != lexer.CONJ && node.Operator != lexer.DISJ {
+		return compiledNode{}, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+
+	operands := make([]compiledNode, len(node.Operands))
+	for i, operand := range node.Operands {
+		compiled, err := Accept[compiledNode](operand, c)
+		if err != nil {
+			return compiledNode{}, err
+		}
+		operands[i] = compiled
+	}
+
+	// A chain stops at the first operand that decides it.
+	conjunction := node.Operator == lexer.CONJ
+	return compiledNode{
+		scalar: func(assignment []bool) bool {
+			for _, operand := range operands {
+				if operand.scalar(assignment) != conjunction {
+					return !conjunction
+				}
+			}
+			return conjunction
+		},
+		word: func(assignment []uint64) uint64 {
+			result := operands[0].word(assignment)
+			for _, operand := range operands[1:] {
+				if conjunction {
+					result &= operand.word(assignment)
+				} else {
+					result |= operand.word(assignment)
+				}
+			}
+			return result
+		},
+	}, nil
+}
+
+func (c *compiler) VisitUnary(node *ast.UnaryNode) (compiledNode, error) {
+	if node.Operator != lexer.NEG {
+		return compiledNode{}, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+	operand, err := Accept[compiledNode](node.Operand, c)
+	if err != nil {
+		return compiledNode{}, err
+	}
+	return compiledNode{
+		scalar: func(assignment []bool) bool { return !operand.scalar(assignment) },
+		word:   func(assignment []uint64) uint64 { return ^operand.word(assignment) },
+	}, nil
+}
+
+func (c *compiler) VisitPredicate(node *ast.PredicateNode) (compiledNode, error) {
+	return compiledNode{}, CompileError{Node: node.String(), Span: node.Span()}
+}
+
+func (c *compiler) VisitQuantifier(node *ast.QuantifierNode) (compiledNode, error) {
+	return compiledNode{}, CompileError{Node: node.String(), Span: node.Span()}
+}
+
+func (c *compiler) VisitFunction(node *ast.FunctionNode) (compiledNode, error) {
+	return compiledNode{}, TermError{Term: node.String(), Span: node.Span()}
+}
@@ -0,0 +1,109 @@
+package visitor
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/parser"
+	"testing"
+)
+
+var compiledFormulas = []string{
+	"a → b",
+	"a ← b",
+	"a ↔ b",
+	"a ∧ b",
+	"a ∨ b",
+	"a ⊕ b",
+	"a ↑ b",
+	"a ↓ b",
+	"!a ∧ 1 ∨ 0",
+	"(a ← b) ↑ (c ↓ d) ⊕ (e → f) ↔ !g",
+	"(a ∧ b ∧ c) ∨ (d ↑ e) ∧ (f ← g)",
+}
+
+// visited returns the column of node computed by visiting it, as the solver
+// does for formulas that cannot be compiled.
+func visited(t *testing.T, node ast.ASTNode) (*BooleanSolver, column) {
+	t.Helper()
+	s := NewBooleanSolver(NewEvaluationContext())
+	s.variables = tableVariables(node)
+	s.rows = 1 << len(s.variables)
+	s.columns = make(map[string]bitVector, len(s.variables))
+	result, err := Accept[column](node, s)
+	if err != nil {
+		t.Fatalf("Accept(%s): %v", node, err)
+	}
+	return s, result
+}
+
+func TestEvalWords(t *testing.T) {
+	for _, input := range compiledFormulas {
+		t.Run(input, func(t *testing.T) {
+			node, err := parser.Parse(input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", input, err)
+			}
+			compiled, err := Compile(node)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", input, err)
+			}
+			s, want := visited(t, node)
+
+			inputs := make([]bitVector, len(compiled.Variables))
+			for i := range inputs {
+				inputs[i] = variableVector(s.rows, len(inputs), i)
+			}
+			assignment := make([]uint64, len(inputs))
+			for w := range want.values {
+				for i, input := range inputs {
+					assignment[i] = input[w]
+				}
+				got := compiled.EvalWords(assignment)
+				if rem := s.rows - 64*w; rem < 64 {
+					got &= 1<<rem - 1
+				}
+				if got != want.values[w] {
+					t.Errorf("word %d: EvalWords = %#x, want %#x", w, got, want.values[w])
+				}
+			}
+		})
+	}
+}
+
+func TestEvalBatch(t *testing.T) {
+	for _, input := range compiledFormulas {
+		node, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		compiled, err := Compile(node)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", input, err)
+		}
+		s, want := visited(t, node)
+
+		// A partial word, a whole one and one row past it.
+		for _, count := range []int{63, 64, 65} {
+			assignments := make([][]bool, count)
+			for row := range assignments {
+				r := row % s.rows
+				assignments[row] = make([]bool, len(compiled.Variables))
+				for i := range compiled.Variables {
+					assignments[row][i] = r>>(len(compiled.Variables)-1-i)&1 == 1
+				}
+			}
+
+			results := compiled.EvalBatch(assignments)
+			if len(results) != count {
+				t.Fatalf("%s: EvalBatch of %d assignments returned %d results", input, count, len(results))
+			}
+			for row, result := range results {
+				if expected := want.values.get(row % s.rows); result != expected {
+					t.Errorf("%s: EvalBatch of %d, row %d = %v, want %v", input, count, row, result, expected)
+				}
+				if scalar := compiled.Eval(assignments[row]); scalar != result {
+					t.Errorf("%s: Eval of row %d = %v, EvalBatch = %v", input, row, scalar, result)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package visitor
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/simplification/rules/base"
+	"slices"
+)
+
+// Derivation is the result of a transformation together with the steps that
+// led to it, in the order they were made.
+type Derivation struct {
+	Result string
+	Steps  []base.RuleApplication
+}
+
+// derivation collects the steps of a transformation.
+type derivation struct {
+	steps []base.RuleApplication
+}
+
+func (d *derivation) record(name, description string, before, after ast.ASTNode) {
+	d.steps = append(d.steps, base.RuleApplication{
+		Name:        name,
+		Description: description,
+		Before:      before.String(),
+		After:       after.String(),
+		Span:        before.Span(),
+	})
+}
+
+// PrenexConverter brings a formula into prenex normal form, a prefix of
+// quantifiers followed by a formula without them. Bounded quantifiers are
+// first replaced by unbounded ones over a predicate named after the set, and
+// every connective is expressed with ∧, ∨ and ¬; quantifiers are then moved
+// out of negations, conjunctions and disjunctions from the inside out,
+// renaming bound variables where moving them would capture a free one.
+type PrenexConverter struct {
+	derivation
+}
+
+func NewPrenexConverter() *PrenexConverter {
+	return &PrenexConverter{}
+}
+
+// Convert returns the prenex normal form of node and the steps taken.
+func (c *PrenexConverter) Convert(node ast.ASTNode) (ast.ASTNode, []base.RuleApplication, error) {
+	c.steps = nil
+	result, err := Accept[ast.ASTNode](node, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, c.steps, nil
+}
+
+func (c *PrenexConverter) VisitGrouping(node *ast.GroupingNode) (ast.ASTNode, error) {
+	// Parentheses are put back where they are needed when nodes are combined.
+	return Accept[ast.ASTNode](node.Expr, c)
+}
+
+func (c *PrenexConverter) VisitLiteral(node *ast.LiteralNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (c *PrenexConverter) VisitVariable(node *ast.VariableNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (c *PrenexConverter) VisitBinary(node *ast.BinaryNode) (ast.ASTNode, error) {
+	left, err := Accept[ast.ASTNode](node.Left, c)
+	if err != nil {
+		return nil, err
+	}
+	right, err := Accept[ast.ASTNode](node.Right, c)
+	if err != nil {
+		return nil, err
+	}
+	span := node.Span()
+	before := binary(node.Operator, left, right, span)
+
+	switch node.Operator {
+	case lexer.CONJ, lexer.DISJ:
+		return c.pull(node.Operator, left, right, span), nil
+	case lexer.IMPL:
+		c.record("Упрощение импликации", "a → b ≡ ¬a ∨ b",
+			before, binary(lexer.DISJ, negation(left, span), right, span))
+		return c.pull(lexer.DISJ, c.negate(left, span), right, span), nil
+	case lexer.CONV:
+		c.record("Упрощение обратной импликации", "a ← b ≡ a ∨ ¬b",
+			before, binary(lexer.DISJ, left, negation(right, span), span))
+		return c.pull(lexer.DISJ, left, c.negate(right, span), span), nil
+	case lexer.EQUIV:
+		c.record("Упрощение эквивалентности", "a ↔ b ≡ (¬a ∨ b) ∧ (a ∨ ¬b)", before, binary(lexer.CONJ,
+			binary(lexer.DISJ, negation(left, span), right, span),
+			binary(lexer.DISJ, left, negation(right, span), span), span))
+		return c.pull(lexer.CONJ,
+			c.pull(lexer.DISJ, c.negate(left, span), right, span),
+			c.pull(lexer.DISJ, left, c.negate(right, span), span), span), nil
+	case lexer.XOR:
+		c.record("Упрощение исключающего ИЛИ", "a ⊕ b ≡ (a ∨ b) ∧ (¬a ∨ ¬b)", before, binary(lexer.CONJ,
+			binary(lexer.DISJ, left, right, span),
+			binary(lexer.DISJ, negation(left, span), negation(right, span), span), span))
+		return c.pull(lexer.CONJ,
+			c.pull(lexer.DISJ, left, right, span),
+			c.pull(lexer.DISJ, c.negate(left, span), c.negate(right, span), span), span), nil
+	case lexer.NAND:
+		c.record("Упрощение штриха Шеффера", "a ↑ b ≡ ¬(a ∧ b)",
+			before, negation(binary(lexer.CONJ, left, right, span), span))
+		return c.negate(c.pull(lexer.CONJ, left, right, span), span), nil
+	case lexer.NOR:
+		c.record("Упрощение стрелки Пирса", "a ↓ b ≡ ¬(a ∨ b)",
+			before, negation(binary(lexer.DISJ, left, right, span), span))
+		return c.negate(c.pull(lexer.DISJ, left, right, span), span), nil
+	default:
+		return nil, OperatorError{Operator: node.Operator.String(), Span: span}
+	}
+}
+
+func (c *PrenexConverter) VisitChain(node *ast.ChainNode) (ast.ASTNode, error) {
+	if node.Operator != lexer.CONJ && node.Operator != lexer.DISJ {
+		return nil, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+
+	var result ast.ASTNode
+	for _, operand := range node.Operands {
+		converted, err := Accept[ast.ASTNode](operand, c)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = converted
+			continue
+		}
+		result = c.pull(node.Operator, result, converted, node.Span())
+	}
+	return result, nil
+}
+
+func (c *PrenexConverter) VisitUnary(node *ast.UnaryNode) (ast.ASTNode, error) {
+	if node.Operator != lexer.NEG {
+		return nil, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+	operand, err := Accept[ast.ASTNode](node.Operand, c)
+	if err != nil {
+		return nil, err
+	}
+	return c.negate(operand, node.Span()), nil
+}
+
+func (c *PrenexConverter) VisitPredicate(node *ast.PredicateNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (c *PrenexConverter) VisitQuantifier(node *ast.QuantifierNode) (ast.ASTNode, error) {
+	body := node.Body
+	if node.Domain != nil {
+		set, ok := node.Domain.(*ast.SetNameNode)
+		if !ok {
+			return nil, NodeTypeError{NodeType: fmt.Sprintf("%T", node.Domain)}
+		}
+		member := ast.NewPredicateNode(set.Name, ast.NewVariableNode(node.Variable))
+		ast.InheritSpan(member, set.Span())
+
+		description := "∀x ∈ S φ ≡ ∀x (S(x) → φ)"
+		body = binary(lexer.IMPL, member, body, node.Span())
+		if node.Type == lexer.EXISTS {
+			description = "∃x ∈ S φ ≡ ∃x (S(x) ∧ φ)"
+			body = binary(lexer.CONJ, member, node.Body, node.Span())
+		}
+		c.record("Релятивизация квантора", description, node, quantifier(node.Type, node.Variable, body, node.Span()))
+	}
+
+	converted, err := Accept[ast.ASTNode](body, c)
+	if err != nil {
+		return nil, err
+	}
+	return quantifier(node.Type, node.Variable, converted, node.Span()), nil
+}
+
+func (c *PrenexConverter) VisitFunction(node *ast.FunctionNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+// negate returns the prenex form of the negation of a prenex formula.
+func (c *PrenexConverter) negate(node ast.ASTNode, span lexer.Span) ast.ASTNode {
+	switch n := node.(type) {
+	case *ast.QuantifierNode:
+		dual, description := lexer.EXISTS, "¬∀x φ ≡ ∃x ¬φ"
+		if n.Type == lexer.EXISTS {
+			dual, description = lexer.FORALL, "¬∃x φ ≡ ∀x ¬φ"
+		}
+		body := ungroup(n.Body)
+		c.record("Отрицание квантора", description,
+			negation(n, span), quantifier(dual, n.Variable, negation(body, span), span))
+		return quantifier(dual, n.Variable, c.negate(body, span), span)
+	case *ast.UnaryNode:
+		if n.Operator == lexer.NEG {
+			c.record("Закон двойного отрицания", "¬¬a ≡ a", negation(n, span), n.Operand)
+			return ungroup(n.Operand)
+		}
+	}
+	return negation(node, span)
+}
+
+// pull combines two prenex formulas with ∧ or ∨ and moves the quantifiers of
+// both in front, those of the left operand first.
+func (c *PrenexConverter) pull(operator lexer.BooleanTokenType, left, right ast.ASTNode, span lexer.Span) ast.ASTNode {
+	before := binary(operator, left, right, span)
+
+	q, fromLeft := left.(*ast.QuantifierNode)
+	other := right
+	if !fromLeft {
+		var ok bool
+		if q, ok = right.(*ast.QuantifierNode); !ok {
+			return before
+		}
+		other = left
+	}
+
+	variable, body := q.Variable, ungroup(q.Body)
+	if slices.Contains(ast.FreeVariables(other), variable) {
+		variable = ast.FreshVariable(variable, slices.Concat(ast.VariableNames(q), ast.VariableNames(other)))
+		body = ast.Substitute(body, q.Variable, ast.NewVariableNode(variable))
+		renamed := quantifier(q.Type, variable, body, q.Span())
+		c.record("Переименование связанной переменной",
+			fmt.Sprintf("%s не должна стать связанной после вынесения квантора", q.Variable), q, renamed)
+	}
+
+	newLeft, newRight := body, right
+	if !fromLeft {
+		newLeft, newRight = left, body
+	}
+	c.record("Вынесение квантора", "(Qx φ) ∘ ψ ≡ Qx (φ ∘ ψ), если x не входит свободно в ψ",
+		before, quantifier(q.Type, variable, binary(operator, newLeft, newRight, span), span))
+	pulled := c.pull(operator, newLeft, newRight, span)
+	return quantifier(q.Type, variable, pulled, span)
+}
+
+// binary builds a binary formula, parenthesizing operands that need it.
+// Operands joined by the same associative operator are left as they are.
+func binary(operator lexer.BooleanTokenType, left, right ast.ASTNode, span lexer.Span) ast.ASTNode {
+	group := func(node ast.ASTNode) ast.ASTNode {
+		if b, ok := node.(*ast.BinaryNode); ok && b.Operator == operator &&
+			(operator == lexer.CONJ || operator == lexer.DISJ) {
+			return node
+		}
+		return parenthesize(node)
+	}
+	node := ast.NewBinaryNode(operator, group(left), group(right))
+	node.SetSpan(span)
+	return node
+}
+
+func negation(node ast.ASTNode, span lexer.Span) ast.ASTNode {
+	negated := ast.NewUnaryNode(lexer.NEG, parenthesize(node))
+	negated.SetSpan(span)
+	return negated
+}
+
+func quantifier(qType lexer.BooleanTokenType, variable string, body ast.ASTNode, span lexer.Span) *ast.QuantifierNode {
+	node := ast.NewQuantifierNode(qType, variable, nil, parenthesize(body))
+	node.SetSpan(span)
+	return node
+}
+
+// parenthesize wraps binary formulas and chains in parentheses.
+func parenthesize(node ast.ASTNode) ast.ASTNode {
+	switch node.(type) {
+	case *ast.BinaryNode, *ast.ChainNode:
+		grouping := ast.NewGroupingNode(node)
+		grouping.SetSpan(node.Span())
+		return grouping
+	default:
+		return node
+	}
+}
+
+func ungroup(node ast.ASTNode) ast.ASTNode {
+	for {
+		grouping, ok := node.(*ast.GroupingNode)
+		if !ok {
+			return node
+		}
+		node = grouping.Expr
+	}
+}
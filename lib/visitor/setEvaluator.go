@@ -0,0 +1,185 @@
+package visitor
+
+import (
+	"cmp"
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/utils"
+	"slices"
+	"strconv"
+)
+
+// UndefinedSetError is returned when an expression refers to a set that has
+// not been defined yet.
+type UndefinedSetError struct {
+	Name string
+	Span lexer.Span
+}
+
+func (e UndefinedSetError) Error() string {
+	return fmt.Sprintf("undefined set %s at position %d", e.Name, e.Span.Start)
+}
+
+// MissingUniverseError is returned when a complement is taken without a universe.
+type MissingUniverseError struct {
+	Span lexer.Span
+}
+
+func (e MissingUniverseError) Error() string {
+	return fmt.Sprintf("complement at position %d needs a universe", e.Span.Start)
+}
+
+// OutsideUniverseError is returned when a set literal mentions an element
+// that is not in the universe.
+type OutsideUniverseError struct {
+	Element string
+	Span    lexer.Span
+}
+
+func (e OutsideUniverseError) Error() string {
+	return fmt.Sprintf("element %s at position %d is not in the universe", e.Element, e.Span.Start)
+}
+
+// SetResult is the value of one statement of a set program. Name is empty
+// for bare expressions; Member is only set for membership tests, which have
+// no Elements.
+type SetResult struct {
+	Statement string
+	Name      string
+	Elements  []string
+	Member    *bool
+}
+
+// SetEvaluator evaluates set programs statement by statement, remembering
+// every definition so that later statements can refer to it. Complements are
+// taken relative to the universe, so they fail when it is nil.
+type SetEvaluator struct {
+	definitions map[string]*utils.Set[string]
+	universe    *utils.Set[string]
+}
+
+func NewSetEvaluator(universe *utils.Set[string]) *SetEvaluator {
+	return &SetEvaluator{
+		definitions: make(map[string]*utils.Set[string]),
+		universe:    universe,
+	}
+}
+
+// Evaluate runs the statements returned by parser.ParseSets in order.
+func (e *SetEvaluator) Evaluate(statements []ast.ASTNode) ([]SetResult, error) {
+	results := make([]SetResult, 0, len(statements))
+
+	for _, statement := range statements {
+		result := SetResult{Statement: statement.String()}
+
+		var expr ast.SetNode
+		switch s := statement.(type) {
+		case *ast.SetDefinitionNode:
+			result.Name = s.Name
+			expr = s.Value
+		case *ast.SetMembershipNode:
+			set, err := AcceptSet[*utils.Set[string]](s.Set, e)
+			if err != nil {
+				return nil, err
+			}
+			member := set.Contains(s.Element)
+			result.Member = &member
+			results = append(results, result)
+			continue
+		case ast.SetNode:
+			expr = s
+		default:
+			return nil, NodeTypeError{NodeType: fmt.Sprintf("%T", s)}
+		}
+
+		set, err := AcceptSet[*utils.Set[string]](expr, e)
+		if err != nil {
+			return nil, err
+		}
+		if result.Name != "" {
+			e.definitions[result.Name] = set
+		}
+
+		result.Elements = sortElements(set.List())
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (e *SetEvaluator) VisitSetName(node *ast.SetNameNode) (*utils.Set[string], error) {
+	set, ok := e.definitions[node.Name]
+	if !ok {
+		return nil, UndefinedSetError{Name: node.Name, Span: node.Span()}
+	}
+	return set, nil
+}
+
+func (e *SetEvaluator) VisitSetLiteral(node *ast.SetLiteralNode) (*utils.Set[string], error) {
+	if e.universe != nil {
+		for _, element := range node.Elements {
+			if !e.universe.Contains(element) {
+				return nil, OutsideUniverseError{Element: element, Span: node.Span()}
+			}
+		}
+	}
+	return utils.NewSetFrom(node.Elements...), nil
+}
+
+func (e *SetEvaluator) VisitSetBinary(node *ast.SetBinaryNode) (*utils.Set[string], error) {
+	left, err := AcceptSet[*utils.Set[string]](node.Left, e)
+	if err != nil {
+		return nil, err
+	}
+	right, err := AcceptSet[*utils.Set[string]](node.Right, e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node.Operator {
+	case lexer.UNION, lexer.ADD:
+		return utils.UnionSet(left, right), nil
+	case lexer.INTERSECT:
+		return utils.IntersectSet(left, right), nil
+	case lexer.SUBSTRACT:
+		return utils.DifferenceSet(left, right), nil
+	case lexer.SYMDIFF:
+		return utils.SymmetricDifferenceSet(left, right), nil
+	default:
+		return nil, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+}
+
+func (e *SetEvaluator) VisitSetGrouping(node *ast.SetGroupingNode) (*utils.Set[string], error) {
+	return AcceptSet[*utils.Set[string]](node.Expr, e)
+}
+
+func (e *SetEvaluator) VisitSetComplement(node *ast.SetComplementNode) (*utils.Set[string], error) {
+	if e.universe == nil {
+		return nil, MissingUniverseError{Span: node.Span()}
+	}
+	operand, err := AcceptSet[*utils.Set[string]](node.Operand, e)
+	if err != nil {
+		return nil, err
+	}
+	return utils.DifferenceSet(e.universe, operand), nil
+}
+
+// sortElements orders elements numerically when they are all integers and
+// lexicographically otherwise.
+func sortElements(elements []string) []string {
+	numbers := make(map[string]int, len(elements))
+	for _, element := range elements {
+		n, err := strconv.Atoi(element)
+		if err != nil {
+			slices.Sort(elements)
+			return elements
+		}
+		numbers[element] = n
+	}
+	slices.SortFunc(elements, func(a, b string) int {
+		return cmp.Compare(numbers[a], numbers[b])
+	})
+	return elements
+}
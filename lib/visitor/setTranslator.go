@@ -0,0 +1,114 @@
+package visitor
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+)
+
+// SetLiteralError is returned when a set expression that has to hold for
+// arbitrary sets mentions concrete elements.
+type SetLiteralError struct {
+	Literal string
+	Span    lexer.Span
+}
+
+func (e SetLiteralError) Error() string {
+	return fmt.Sprintf("set literal %s at position %d cannot be translated, only named sets and {} can", e.Literal, e.Span.Start)
+}
+
+// SetTranslator turns a set expression into the boolean formula describing
+// membership of an arbitrary element x in it. Every set name A becomes the
+// variable A, read as "x ∈ A":
+//
+//	x ∈ A ∪ B  ⇔  A ∨ B
+//	x ∈ A ∩ B  ⇔  A ∧ B
+//	x ∈ A \ B  ⇔  A ∧ ¬B
+//	x ∈ A ⊕ B  ⇔  A ⊕ B
+//	x ∈ A'     ⇔  ¬A
+//
+// The empty set becomes false; other literals have no translation.
+type SetTranslator struct{}
+
+func NewSetTranslator() *SetTranslator {
+	return &SetTranslator{}
+}
+
+func (t *SetTranslator) Translate(node ast.SetNode) (ast.ASTNode, error) {
+	return AcceptSet[ast.ASTNode](node, t)
+}
+
+func (t *SetTranslator) VisitSetName(node *ast.SetNameNode) (ast.ASTNode, error) {
+	variable := ast.NewVariableNode(node.Name)
+	variable.SetSpan(node.Span())
+	return variable, nil
+}
+
+func (t *SetTranslator) VisitSetLiteral(node *ast.SetLiteralNode) (ast.ASTNode, error) {
+	if len(node.Elements) > 0 {
+		return nil, SetLiteralError{Literal: node.String(), Span: node.Span()}
+	}
+	literal := ast.NewLiteralNode(false)
+	literal.SetSpan(node.Span())
+	return literal, nil
+}
+
+func (t *SetTranslator) VisitSetBinary(node *ast.SetBinaryNode) (ast.ASTNode, error) {
+	left, err := AcceptSet[ast.ASTNode](node.Left, t)
+	if err != nil {
+		return nil, err
+	}
+	right, err := AcceptSet[ast.ASTNode](node.Right, t)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ast.ASTNode
+	switch node.Operator {
+	case lexer.UNION, lexer.ADD:
+		result = ast.NewBinaryNode(lexer.DISJ, left, right)
+	case lexer.INTERSECT:
+		result = ast.NewBinaryNode(lexer.CONJ, left, right)
+	case lexer.SUBSTRACT:
+		negated := ast.NewUnaryNode(lexer.NEG, groupOperand(right))
+		negated.SetSpan(node.Right.Span())
+		result = ast.NewBinaryNode(lexer.CONJ, left, negated)
+	case lexer.SYMDIFF:
+		result = ast.NewBinaryNode(lexer.XOR, left, right)
+	default:
+		return nil, OperatorError{Operator: node.Operator.String(), Span: node.Span()}
+	}
+	result.SetSpan(node.Span())
+	return result, nil
+}
+
+func (t *SetTranslator) VisitSetGrouping(node *ast.SetGroupingNode) (ast.ASTNode, error) {
+	expr, err := AcceptSet[ast.ASTNode](node.Expr, t)
+	if err != nil {
+		return nil, err
+	}
+	grouping := ast.NewGroupingNode(expr)
+	grouping.SetSpan(node.Span())
+	return grouping, nil
+}
+
+func (t *SetTranslator) VisitSetComplement(node *ast.SetComplementNode) (ast.ASTNode, error) {
+	operand, err := AcceptSet[ast.ASTNode](node.Operand, t)
+	if err != nil {
+		return nil, err
+	}
+	negated := ast.NewUnaryNode(lexer.NEG, groupOperand(operand))
+	negated.SetSpan(node.Span())
+	return negated, nil
+}
+
+// groupOperand parenthesizes binary formulas so that a negation placed in
+// front of them prints the way it is meant.
+func groupOperand(node ast.ASTNode) ast.ASTNode {
+	if _, ok := node.(*ast.BinaryNode); !ok {
+		return node
+	}
+	grouping := ast.NewGroupingNode(node)
+	grouping.SetSpan(node.Span())
+	return grouping
+}
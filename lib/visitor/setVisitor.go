@@ -0,0 +1,34 @@
+package visitor
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+)
+
+// SetVisitor defines the interface for set expression visitors.
+type SetVisitor[T any] interface {
+	VisitSetName(node *ast.SetNameNode) (T, error)
+	VisitSetLiteral(node *ast.SetLiteralNode) (T, error)
+	VisitSetBinary(node *ast.SetBinaryNode) (T, error)
+	VisitSetGrouping(node *ast.SetGroupingNode) (T, error)
+	VisitSetComplement(node *ast.SetComplementNode) (T, error)
+}
+
+// AcceptSet dispatches the appropriate set visitor method based on the node type.
+func AcceptSet[T any](node ast.SetNode, visitor SetVisitor[T]) (T, error) {
+	switch n := node.(type) {
+	case *ast.SetNameNode:
+		return visitor.VisitSetName(n)
+	case *ast.SetLiteralNode:
+		return visitor.VisitSetLiteral(n)
+	case *ast.SetBinaryNode:
+		return visitor.VisitSetBinary(n)
+	case *ast.SetGroupingNode:
+		return visitor.VisitSetGrouping(n)
+	case *ast.SetComplementNode:
+		return visitor.VisitSetComplement(n)
+	default:
+		var zero T
+		return zero, NodeTypeError{NodeType: fmt.Sprintf("%T", n)}
+	}
+}
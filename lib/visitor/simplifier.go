@@ -54,6 +54,7 @@ func (s *Simplifier) VisitGrouping(node *ast.GroupingNode) (ast.ASTNode, error)
 		return nil, err
 	}
 	current := ast.NewGroupingNode(expr)
+	current.SetSpan(node.Span())
 
 	simplified, err := s.applyAllRuleSets(current)
 	if err != nil {
@@ -92,6 +93,7 @@ func (s *Simplifier) VisitBinary(node *ast.BinaryNode) (ast.ASTNode, error) {
 	}
 
 	current := ast.NewBinaryNode(node.Operator, left, right)
+	current.SetSpan(node.Span())
 
 	return s.applyAllRuleSets(current)
 }
@@ -110,10 +112,13 @@ func (s *Simplifier) VisitChain(node *ast.ChainNode) (ast.ASTNode, error) {
 		simplified = append(simplified, simplifiedOperand)
 	}
 
-	current, err := s.applyAllRuleSets(&ast.ChainNode{
+	rebuilt := &ast.ChainNode{
 		Operator: node.Operator,
 		Operands: simplified,
-	})
+	}
+	rebuilt.SetSpan(node.Span())
+
+	current, err := s.applyAllRuleSets(rebuilt)
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +145,7 @@ Outer:
 		for j := i - 1; j >= 0; j-- {
 			other := operands[j]
 			combination := ast.NewBinaryNode(node.Operator, one, other)
+			combination.SetSpan(one.Span().Cover(other.Span()))
 			simplifiedCombination, err := Accept[ast.ASTNode](combination, s)
 			if err != nil {
 				return nil, err
@@ -164,17 +170,15 @@ Outer:
 	switch len(newOperands) {
 	case 0:
 		result = ast.NewLiteralNode(node.Operator == lexer.DISJ)
-		return result, nil
 	case 1:
 		result = newOperands[0]
-		return result, nil
 	case 2:
 		result = ast.NewBinaryNode(node.Operator, newOperands[0], newOperands[1])
-		return result, nil
 	default:
 		result = &ast.ChainNode{Operator: node.Operator, Operands: newOperands}
-		return result, nil
 	}
+	ast.InheritSpan(result, node.Span())
+	return result, nil
 }
 
 func (s *Simplifier) VisitUnary(node *ast.UnaryNode) (ast.ASTNode, error) {
@@ -184,6 +188,7 @@ func (s *Simplifier) VisitUnary(node *ast.UnaryNode) (ast.ASTNode, error) {
 	}
 
 	current := ast.NewUnaryNode(node.Operator, operand)
+	current.SetSpan(node.Span())
 	return s.applyAllRuleSets(current)
 }
 
@@ -192,7 +197,18 @@ func (s *Simplifier) VisitPredicate(node *ast.PredicateNode) (ast.ASTNode, error
 }
 
 func (s *Simplifier) VisitQuantifier(node *ast.QuantifierNode) (ast.ASTNode, error) {
-	return s.applyAllRuleSets(node)
+	body, err := Accept[ast.ASTNode](node.Body, s)
+	if err != nil {
+		return nil, err
+	}
+
+	current := ast.NewQuantifierNode(node.Type, node.Variable, node.Domain, body)
+	current.SetSpan(node.Span())
+	return s.applyAllRuleSets(current)
+}
+
+func (s *Simplifier) VisitFunction(node *ast.FunctionNode) (ast.ASTNode, error) {
+	return node, nil
 }
 
 func (s *Simplifier) applyAllRuleSets(node ast.ASTNode) (ast.ASTNode, error) {
@@ -217,9 +233,9 @@ func (s *Simplifier) canRemoveGrouping(node ast.ASTNode) bool {
 	}
 
 	switch grouping.Expr.(type) {
-	case *ast.LiteralNode, *ast.VariableNode:
+	case *ast.LiteralNode, *ast.VariableNode, *ast.PredicateNode:
 		return true
-	case *ast.UnaryNode:
+	case *ast.UnaryNode, *ast.QuantifierNode:
 		return true
 	case *ast.GroupingNode:
 		return true
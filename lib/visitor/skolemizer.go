@@ -0,0 +1,128 @@
+package visitor
+
+import (
+	"logicka/lib/ast"
+	"logicka/lib/lexer"
+	"logicka/lib/simplification/rules/base"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Skolemizer removes the existential quantifiers of a formula in prenex
+// normal form. The variable of ∃y is replaced by a new function applied to
+// the variables of the universal quantifiers in front of it that its body
+// mentions, or by a new constant when there are none. The result is
+// satisfiable exactly when the formula is, which is all clause form needs.
+type Skolemizer struct {
+	derivation
+	universal []string // variables of the enclosing universal quantifiers
+	taken     []string // names the new symbols must not take
+}
+
+func NewSkolemizer() *Skolemizer {
+	return &Skolemizer{}
+}
+
+// Skolemize converts node to prenex normal form and Skolemizes it. The steps
+// of both conversions are returned.
+func (s *Skolemizer) Skolemize(node ast.ASTNode) (ast.ASTNode, []base.RuleApplication, error) {
+	prenex, steps, err := NewPrenexConverter().Convert(node)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signature, err := ast.SignatureOf(prenex)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.steps = steps
+	s.universal = nil
+	s.taken = slices.Concat(ast.VariableNames(prenex), slices.Collect(maps.Keys(signature.Functions)))
+
+	result, err := Accept[ast.ASTNode](prenex, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, s.steps, nil
+}
+
+func (s *Skolemizer) VisitGrouping(node *ast.GroupingNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (s *Skolemizer) VisitLiteral(node *ast.LiteralNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (s *Skolemizer) VisitVariable(node *ast.VariableNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (s *Skolemizer) VisitBinary(node *ast.BinaryNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (s *Skolemizer) VisitChain(node *ast.ChainNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (s *Skolemizer) VisitUnary(node *ast.UnaryNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (s *Skolemizer) VisitPredicate(node *ast.PredicateNode) (ast.ASTNode, error) {
+	return node, nil
+}
+
+func (s *Skolemizer) VisitQuantifier(node *ast.QuantifierNode) (ast.ASTNode, error) {
+	body := ungroup(node.Body)
+
+	if node.Type == lexer.FORALL {
+		s.universal = append(s.universal, node.Variable)
+		defer func() { s.universal = s.universal[:len(s.universal)-1] }()
+
+		skolemized, err := Accept[ast.ASTNode](body, s)
+		if err != nil {
+			return nil, err
+		}
+		return quantifier(node.Type, node.Variable, skolemized, node.Span()), nil
+	}
+
+	// Universal variables the body does not mention cannot influence the
+	// choice of the witness and are left out of the Skolem function.
+	var dependencies []string
+	for _, variable := range s.universal {
+		if slices.Contains(ast.FreeVariables(body), variable) {
+			dependencies = append(dependencies, variable)
+		}
+	}
+
+	var term ast.ASTNode
+	var description string
+	if len(dependencies) == 0 {
+		name := ast.FreshVariable("c", s.taken)
+		term = ast.NewVariableNode(name)
+		description = "∃" + node.Variable + " заменяется новой константой " + name
+		s.taken = append(s.taken, name)
+	} else {
+		args := make([]ast.ASTNode, len(dependencies))
+		for i, variable := range dependencies {
+			args[i] = ast.NewVariableNode(variable)
+		}
+		function := ast.NewFunctionNode(ast.FreshVariable("f", s.taken), args...)
+		term = function
+		description = "∃" + node.Variable + " заменяется новой функцией " + function.Name +
+			" от " + strings.Join(dependencies, ", ")
+		s.taken = append(s.taken, function.Name)
+	}
+	ast.InheritSpan(term, node.Span())
+
+	body = ast.Substitute(body, node.Variable, term)
+	s.record("Сколемизация", description, node, body)
+	return Accept[ast.ASTNode](body, s)
+}
+
+func (s *Skolemizer) VisitFunction(node *ast.FunctionNode) (ast.ASTNode, error) {
+	return node, nil
+}
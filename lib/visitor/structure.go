@@ -0,0 +1,171 @@
+package visitor
+
+import (
+	"fmt"
+	"logicka/lib/ast"
+	"slices"
+	"strings"
+)
+
+// FunctionMapping is one row of the table of a function: the value it takes
+// on the given arguments.
+type FunctionMapping struct {
+	Args  []string
+	Value string
+}
+
+// Structure interprets the symbols of a first-order formula over a finite
+// domain. Predicates are relations given as the list of tuples they hold on,
+// functions are tables, and constants name domain elements. A term variable
+// that is neither bound nor a constant must itself be a domain element. Sets
+// are the subsets of the domain bounded quantifiers such as ∀x ∈ S range over.
+type Structure struct {
+	Domain     []string
+	Predicates map[string][][]string
+	Functions  map[string][]FunctionMapping
+	Constants  map[string]string
+	Sets       map[string][]string
+
+	relations map[string]map[string]bool   // tuple keys of Predicates
+	tables    map[string]map[string]string // tuple keys of Functions
+}
+
+// StructureError reports a structure that does not fit the formula it is
+// used with.
+type StructureError struct {
+	Message string
+}
+
+func (e StructureError) Error() string {
+	return e.Message
+}
+
+// Check verifies that the structure interprets every symbol of signature
+// with the right arity and only mentions elements of its domain.
+func (s *Structure) Check(signature *ast.Signature) error {
+	if len(s.Domain) == 0 {
+		return StructureError{Message: "the domain must not be empty"}
+	}
+	checkElements := func(what string, elements ...string) error {
+		for _, element := range elements {
+			if !slices.Contains(s.Domain, element) {
+				return StructureError{Message: fmt.Sprintf("%s: %s is not in the domain", what, element)}
+			}
+		}
+		return nil
+	}
+
+	for name, arity := range signature.Predicates {
+		tuples, ok := s.Predicates[name]
+		if !ok {
+			return StructureError{Message: fmt.Sprintf("predicate %s is not interpreted", name)}
+		}
+		for _, tuple := range tuples {
+			if len(tuple) != arity {
+				return StructureError{Message: fmt.Sprintf("predicate %s has arity %d but holds on (%s)",
+					name, arity, strings.Join(tuple, ", "))}
+			}
+			if err := checkElements("predicate "+name, tuple...); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, arity := range signature.Functions {
+		mappings, ok := s.Functions[name]
+		if !ok {
+			return StructureError{Message: fmt.Sprintf("function %s is not interpreted", name)}
+		}
+		for _, mapping := range mappings {
+			if len(mapping.Args) != arity {
+				return StructureError{Message: fmt.Sprintf("function %s has arity %d but is defined on (%s)",
+					name, arity, strings.Join(mapping.Args, ", "))}
+			}
+			if err := checkElements("function "+name, append(slices.Clone(mapping.Args), mapping.Value)...); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, element := range s.Constants {
+		if err := checkElements("constant "+name, element); err != nil {
+			return err
+		}
+	}
+
+	for name, elements := range s.Sets {
+		if err := checkElements("set "+name, elements...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// elements returns the elements a quantifier ranges over: its set when it
+// is bounded and the whole domain otherwise.
+func (s *Structure) elements(node *ast.QuantifierNode) ([]string, error) {
+	if node.Domain == nil {
+		return s.Domain, nil
+	}
+	set, ok := node.Domain.(*ast.SetNameNode)
+	if !ok {
+		return nil, NodeTypeError{NodeType: fmt.Sprintf("%T", node.Domain)}
+	}
+	elements, ok := s.Sets[set.Name]
+	if !ok {
+		return nil, UndefinedSetError{Name: set.Name, Span: set.Span()}
+	}
+	return elements, nil
+}
+
+// holds reports whether the predicate holds on args.
+func (s *Structure) holds(name string, args []string) (bool, error) {
+	s.index()
+	relation, ok := s.relations[name]
+	if !ok {
+		return false, StructureError{Message: fmt.Sprintf("predicate %s is not interpreted", name)}
+	}
+	return relation[tupleKey(args)], nil
+}
+
+// apply returns the value of the function on args.
+func (s *Structure) apply(name string, args []string) (string, error) {
+	s.index()
+	table, ok := s.tables[name]
+	if !ok {
+		return "", StructureError{Message: fmt.Sprintf("function %s is not interpreted", name)}
+	}
+	value, ok := table[tupleKey(args)]
+	if !ok {
+		return "", StructureError{Message: fmt.Sprintf("function %s is not defined on (%s)", name, strings.Join(args, ", "))}
+	}
+	return value, nil
+}
+
+// index builds the lookup tables of the relations and functions once.
+func (s *Structure) index() {
+	if s.relations != nil {
+		return
+	}
+
+	s.relations = make(map[string]map[string]bool, len(s.Predicates))
+	for name, tuples := range s.Predicates {
+		s.relations[name] = make(map[string]bool, len(tuples))
+		for _, tuple := range tuples {
+			s.relations[name][tupleKey(tuple)] = true
+		}
+	}
+
+	s.tables = make(map[string]map[string]string, len(s.Functions))
+	for name, mappings := range s.Functions {
+		s.tables[name] = make(map[string]string, len(mappings))
+		for _, mapping := range mappings {
+			s.tables[name][tupleKey(mapping.Args)] = mapping.Value
+		}
+	}
+}
+
+func tupleKey(elements []string) string {
+	return strings.Join(elements, "\x00")
+}
@@ -64,14 +64,40 @@ func (t *TreePrinter) VisitUnary(node *ast.UnaryNode) (interface{}, error) {
 
 func (t *TreePrinter) VisitPredicate(node *ast.PredicateNode) (interface{}, error) {
 	t.printIndent(fmt.Sprintf("Predicate: %s", node.Name))
-	return nil, nil
+	return nil, t.printArgs(node.Args)
 }
 
 func (t *TreePrinter) VisitQuantifier(node *ast.QuantifierNode) (interface{}, error) {
-	t.printIndent(fmt.Sprintf("Quantifier: %s %s",
-		node.Type.String(),
-		node.Variable))
-	return nil, nil
+	if node.Domain != nil {
+		t.printIndent(fmt.Sprintf("Quantifier: %s %s ∈ %s",
+			node.Type.String(),
+			node.Variable,
+			node.Domain.String()))
+	} else {
+		t.printIndent(fmt.Sprintf("Quantifier: %s %s",
+			node.Type.String(),
+			node.Variable))
+	}
+	t.indentLevel++
+	_, err := Accept[interface{}](node.Body, t)
+	t.indentLevel--
+	return nil, err
+}
+
+func (t *TreePrinter) VisitFunction(node *ast.FunctionNode) (interface{}, error) {
+	t.printIndent(fmt.Sprintf("Function: %s", node.Name))
+	return nil, t.printArgs(node.Args)
+}
+
+func (t *TreePrinter) printArgs(args []ast.ASTNode) error {
+	t.indentLevel++
+	defer func() { t.indentLevel-- }()
+	for _, arg := range args {
+		if _, err := Accept[interface{}](arg, t); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (t *TreePrinter) printIndent(text string) {
@@ -0,0 +1,217 @@
+package visitor
+
+import (
+	"errors"
+	"logicka/lib/ast"
+	"math/bits"
+	"slices"
+)
+
+// MaxPageVariables bounds the number of variables of a table that is read
+// page by page. Only the rows of the page are computed, but row numbers must
+// fit in an int.
+const MaxPageVariables = 62
+
+// DefaultPageSize is the number of rows of a page when none is given.
+const DefaultPageSize = 256
+
+// MaxScanWords bounds the words of 64 rows a page may look at. A filtered
+// page of a sparse table ends there, so that a request returns in bounded
+// time however many rows are left out.
+const MaxScanWords = 1 << 16
+
+// ErrCancelled is returned by a scan that was cancelled, see SetCancel.
+var ErrCancelled = errors.New("truth table computation was cancelled")
+
+// RowFilter selects the rows of a truth table by their result.
+type RowFilter string
+
+const (
+	AllRows   RowFilter = "all"
+	TrueRows  RowFilter = "true"
+	FalseRows RowFilter = "false"
+)
+
+// selectRows returns the bits of the rows of a word of results the filter keeps.
+func (f RowFilter) selectRows(results uint64) uint64 {
+	switch f {
+	case TrueRows:
+		return results
+	case FalseRows:
+		return ^results
+	default:
+		return ^uint64(0)
+	}
+}
+
+// TruthTablePage is a range of the rows of a truth table that pass a filter.
+// TotalRows counts every row of the table. More tells whether the table goes
+// on after the page, and Next is the number of the row to read on from. A
+// page that stopped at MaxScanWords may have fewer rows than asked for, even
+// none; Skip is then the number of rows of the offset that are still to be
+// left out from Next on.
+type TruthTablePage struct {
+	Variables []string // the enumerated variables, in table order
+	TotalRows int
+	Rows      []TruthTableEntry
+	More      bool
+	Next      int
+	Skip      int
+}
+
+// SolvePage returns at most limit rows of the truth table of node that pass
+// filter, skipping the first offset of them. Rows are computed directly from
+// their numbers, 64 at a time, so a page of the unfiltered table costs only
+// its own rows while a filtered page has to look at every row before it, up
+// to MaxScanWords words of them; SolveRows reads on from where a page ended
+// without that cost. Formulas with quantifiers or predicates are evaluated on
+// the whole table, of at most MaxTableVariables variables, and cut into
+// pages.
+func (s *BooleanSolver) SolvePage(node ast.ASTNode, offset, limit int, filter RowFilter) (*TruthTablePage, error) {
+	offset = max(offset, 0)
+	if filter == AllRows || filter == "" {
+		return s.scan(node, offset, 0, limit, filter)
+	}
+	return s.scan(node, 0, offset, limit, filter)
+}
+
+// SolveRows returns at most limit rows of the truth table of node that pass
+// filter, starting at the row numbered from. A solver that reads the pages of
+// a formula with quantifiers or predicates one after another evaluates its
+// table only once.
+func (s *BooleanSolver) SolveRows(node ast.ASTNode, from, limit int, filter RowFilter) (*TruthTablePage, error) {
+	return s.scan(node, max(from, 0), 0, limit, filter)
+}
+
+// SetCancel makes SolvePage and SolveRows call cancelled before each word of
+// rows they look at and stop with ErrCancelled once it returns true.
+func (s *BooleanSolver) SetCancel(cancelled func() bool) {
+	s.cancelled = cancelled
+}
+
+// scan collects the rows numbered from on that pass filter, leaving out the
+// first skip of them. It looks at no more than MaxScanWords words of rows.
+func (s *BooleanSolver) scan(node ast.ASTNode, from, skip, limit int, filter RowFilter) (*TruthTablePage, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	compiled, err := Compile(node)
+	if err != nil {
+		return s.scanWhole(node, from, skip, limit, filter)
+	}
+	if err := s.layout(node, MaxPageVariables); err != nil {
+		return nil, err
+	}
+	page := &TruthTablePage{Variables: append([]string{}, s.variables...), TotalRows: s.rows, Rows: []TruthTableEntry{}, Next: s.rows}
+
+	// Each slot of the compiled formula holds either a fixed word or the
+	// column of an enumerated variable.
+	indices := make([]int, len(compiled.Variables))
+	assignment := make([]uint64, len(compiled.Variables))
+	for i, name := range compiled.Variables {
+		indices[i] = slices.Index(s.variables, name)
+		if s.context.Variables[name] {
+			assignment[i] = ^uint64(0)
+		}
+	}
+
+	for w := from / 64; w < words(s.rows); w++ {
+		if s.cancelled != nil && s.cancelled() {
+			return nil, ErrCancelled
+		}
+		if w-from/64 == MaxScanWords {
+			page.More, page.Next, page.Skip = true, w*64, skip
+			return page, nil
+		}
+
+		for i, index := range indices {
+			if index >= 0 {
+				assignment[i] = variableWord(len(s.variables), index, w)
+			}
+		}
+		results := compiled.EvalWords(assignment)
+
+		selected := filter.selectRows(results)
+		if rest := s.rows - w*64; rest < 64 {
+			selected &= 1<<rest - 1
+		}
+		if w == from/64 {
+			selected &^= 1<<(from%64) - 1
+		}
+		if count := bits.OnesCount64(selected); skip >= count {
+			skip -= count
+			continue
+		}
+
+		for ; selected != 0; selected &= selected - 1 {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			row := w*64 + bits.TrailingZeros64(selected)
+			if len(page.Rows) == limit {
+				page.More, page.Next = true, row
+				return page, nil
+			}
+			page.Rows = append(page.Rows, s.entry(row, results>>(row%64)&1 == 1))
+		}
+	}
+	return page, nil
+}
+
+// wholeTable is the column of a formula scanWhole evaluated, kept for the
+// pages that follow.
+type wholeTable struct {
+	node   ast.ASTNode
+	result column
+}
+
+// scanWhole is scan for formulas that cannot be compiled: they are evaluated
+// on the whole table at once, and only the rows of the page are listed. The
+// table is kept, so the next page of the same node is listed from it.
+func (s *BooleanSolver) scanWhole(node ast.ASTNode, from, skip, limit int, filter RowFilter) (*TruthTablePage, error) {
+	if s.whole == nil || s.whole.node != node {
+		result, err := s.table(node, MaxTableVariables)
+		if err != nil {
+			return nil, err
+		}
+		s.whole = &wholeTable{node: node, result: result}
+	} else if err := s.layout(node, MaxTableVariables); err != nil {
+		return nil, err
+	}
+	result := s.whole.result
+
+	page := &TruthTablePage{Variables: append([]string{}, s.variables...), TotalRows: s.rows, Rows: []TruthTableEntry{}, Next: s.rows}
+	for row := from; row < s.rows; row++ {
+		if row%64 == 0 {
+			if s.cancelled != nil && s.cancelled() {
+				return nil, ErrCancelled
+			}
+			if row/64-from/64 == MaxScanWords {
+				page.More, page.Next, page.Skip = true, row, skip
+				break
+			}
+		}
+		if filter.selectRows(boolWord(result.values.get(row)))&1 == 0 {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		if len(page.Rows) == limit {
+			page.More, page.Next = true, row
+			break
+		}
+		page.Rows = append(page.Rows, s.explainedEntry(row, result))
+	}
+	return page, nil
+}
+
+func boolWord(value bool) uint64 {
+	if value {
+		return 1
+	}
+	return 0
+}
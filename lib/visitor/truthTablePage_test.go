@@ -0,0 +1,155 @@
+package visitor_test
+
+import (
+	"fmt"
+	"logicka/lib/parser"
+	"logicka/lib/visitor"
+	"slices"
+	"testing"
+)
+
+// pageFormulas cover compiled formulas with more than a word of rows and a
+// quantified one, which is paged from the whole table.
+var pageFormulas = []struct {
+	input   string
+	context func() *visitor.EvaluationContext
+}{
+	{"(a ∧ b) ⊕ (c ∨ !d) → (e ~ f) ∧ g", visitor.NewEvaluationContext},
+	{"(a ∧ b) ⊕ (c ∨ !d) → (e ~ f) ∧ g", func() *visitor.EvaluationContext {
+		return &visitor.EvaluationContext{Variables: map[string]bool{"c": false}, Order: []string{"g", "e"}}
+	}},
+	{"∀x (x ∨ a) ∧ (b ⊕ c)", visitor.NewEvaluationContext},
+}
+
+var pageFilters = []visitor.RowFilter{visitor.AllRows, visitor.TrueRows, visitor.FalseRows}
+
+// filtered returns the rows of the table of input that pass filter, as Solve
+// computes them.
+func filtered(t *testing.T, input string, ctx *visitor.EvaluationContext, filter visitor.RowFilter) []visitor.TruthTableEntry {
+	t.Helper()
+	node, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+	table, err := visitor.NewBooleanSolver(ctx).Solve(node)
+	if err != nil {
+		t.Fatalf("Solve(%q): %v", input, err)
+	}
+	return slices.DeleteFunc(table, func(entry visitor.TruthTableEntry) bool {
+		return filter == visitor.TrueRows && !entry.Result || filter == visitor.FalseRows && entry.Result
+	})
+}
+
+// sameRows compares rows by minterm, result and assignment.
+func sameRows(t *testing.T, got, want []visitor.TruthTableEntry) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Minterm != want[i].Minterm || got[i].Result != want[i].Result || !slices.Equal(got[i].Variables, want[i].Variables) {
+			t.Fatalf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSolvePage(t *testing.T) {
+	for _, tt := range pageFormulas {
+		for _, filter := range pageFilters {
+			for _, limit := range []int{1, 7, 64, 100} {
+				t.Run(fmt.Sprintf("%s/%s/%d", tt.input, filter, limit), func(t *testing.T) {
+					want := filtered(t, tt.input, tt.context(), filter)
+					node, err := parser.Parse(tt.input)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					var rows []visitor.TruthTableEntry
+					for offset := 0; ; offset += limit {
+						page, err := visitor.NewBooleanSolver(tt.context()).SolvePage(node, offset, limit, filter)
+						if err != nil {
+							t.Fatalf("SolvePage(%d, %d): %v", offset, limit, err)
+						}
+						rows = append(rows, page.Rows...)
+						if !page.More {
+							// The last page holds what is left, possibly less than a page.
+							if want := len(want) - offset; len(page.Rows) != want {
+								t.Fatalf("last page at %d has %d rows, want %d", offset, len(page.Rows), want)
+							}
+							break
+						}
+						if len(page.Rows) != limit {
+							t.Fatalf("page at %d has %d rows, want %d", offset, len(page.Rows), limit)
+						}
+					}
+					sameRows(t, rows, want)
+				})
+			}
+		}
+	}
+}
+
+func TestSolveRows(t *testing.T) {
+	for _, tt := range pageFormulas {
+		for _, filter := range pageFilters {
+			t.Run(fmt.Sprintf("%s/%s", tt.input, filter), func(t *testing.T) {
+				want := filtered(t, tt.input, tt.context(), filter)
+				node, err := parser.Parse(tt.input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var rows []visitor.TruthTableEntry
+				for from := 0; ; {
+					page, err := visitor.NewBooleanSolver(tt.context()).SolveRows(node, from, 5, filter)
+					if err != nil {
+						t.Fatalf("SolveRows(%d): %v", from, err)
+					}
+					rows = append(rows, page.Rows...)
+					if !page.More {
+						if page.Next != page.TotalRows {
+							t.Errorf("last page continues at %d, want %d", page.Next, page.TotalRows)
+						}
+						break
+					}
+					if page.Next <= from {
+						t.Fatalf("page at %d continues at %d", from, page.Next)
+					}
+					from = page.Next
+				}
+				sameRows(t, rows, want)
+			})
+		}
+	}
+}
+
+func TestSolvePageMinterms(t *testing.T) {
+	node, err := parser.Parse("a ∨ b ∨ c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &visitor.EvaluationContext{Order: []string{"c"}}
+	page, err := visitor.NewBooleanSolver(ctx).SolvePage(node, 2, 3, visitor.AllRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(page.Variables, []string{"c", "a", "b"}) {
+		t.Errorf("variables = %v, want [c a b]", page.Variables)
+	}
+	for i, entry := range page.Rows {
+		if entry.Minterm != 2+i {
+			t.Errorf("row %d has minterm %d, want %d", i, entry.Minterm, 2+i)
+		}
+		minterm := 0
+		for _, variable := range entry.Variables {
+			minterm <<= 1
+			if variable.Value {
+				minterm |= 1
+			}
+		}
+		if minterm != entry.Minterm {
+			t.Errorf("row %d assigns %v, which is minterm %d, not %d", i, entry.Variables, minterm, entry.Minterm)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package visitor
+
+import (
+	"errors"
+	"fmt"
+	"logicka/lib/ast"
+	"logicka/lib/parser"
+	"strings"
+	"testing"
+)
+
+func TestScanWholeKeepsTable(t *testing.T) {
+	node, err := parser.Parse("∀x (x ∨ a) ∧ (b ⊕ c)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := parser.Parse("∃x (x ∧ a)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewBooleanSolver(NewEvaluationContext())
+	page, err := s.SolveRows(node, 0, 3, AllRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	whole := s.whole
+	if whole == nil {
+		t.Fatal("the table of the first page was not kept")
+	}
+
+	if page, err = s.SolveRows(node, page.Next, 3, AllRows); err != nil {
+		t.Fatal(err)
+	}
+	if s.whole != whole {
+		t.Error("the next page of the same formula evaluated the table again")
+	}
+	if page.Rows[0].Minterm != 3 || len(page.Variables) != 3 {
+		t.Errorf("next page starts at minterm %d over %v, want 3 over [a b c]", page.Rows[0].Minterm, page.Variables)
+	}
+
+	if _, err := s.SolveRows(other, 0, 3, AllRows); err != nil {
+		t.Fatal(err)
+	}
+	if s.whole == whole || s.whole.node != other {
+		t.Error("a page of another formula was listed from the kept table")
+	}
+}
+
+// names returns count variable names with the given prefix, in order.
+func names(prefix string, count int) []string {
+	list := make([]string, count)
+	for i := range list {
+		list[i] = fmt.Sprintf("%s%d", prefix, i+10)
+	}
+	return list
+}
+
+func parse(t *testing.T, input string) ast.ASTNode {
+	t.Helper()
+	node, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+	return node
+}
+
+func TestScanBound(t *testing.T) {
+	// A conjunction is true on the last row of its table only.
+	node := parse(t, strings.Join(names("v", 40), " ∧ "))
+	s := NewBooleanSolver(NewEvaluationContext())
+
+	page, err := s.SolveRows(node, 5, 10, TrueRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Rows) != 0 || !page.More || page.Next != MaxScanWords*64 || page.Skip != 0 {
+		t.Errorf("page = %d rows, More %v, Next %d, Skip %d, want none, true, %d, 0", len(page.Rows), page.More, page.Next, page.Skip, MaxScanWords*64)
+	}
+
+	// The last six variables change within a word, so this is true on the
+	// last row of every word.
+	node = parse(t, "("+strings.Join(names("a", 24), " ∨ ")+" ∨ 1) ∧ "+strings.Join(names("v", 6), " ∧ "))
+	page, err = s.SolvePage(node, MaxScanWords+5, 10, TrueRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Rows) != 0 || !page.More || page.Next != MaxScanWords*64 || page.Skip != 5 {
+		t.Errorf("page = %d rows, More %v, Next %d, Skip %d, want none, true, %d, 5", len(page.Rows), page.More, page.Next, page.Skip, MaxScanWords*64)
+	}
+
+	page, err = s.SolvePage(node, 5, 10, TrueRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Rows) != 10 || !page.More || page.Skip != 0 || page.Rows[0].Minterm != 5*64+63 {
+		t.Errorf("page = %d rows from minterm %d, More %v, Skip %d, want 10 from %d, true, 0", len(page.Rows), page.Rows[0].Minterm, page.More, page.Skip, 5*64+63)
+	}
+}
+
+func TestScanCancelled(t *testing.T) {
+	tests := []string{
+		"",
+		// Formulas with quantifiers are checked while their rows are listed.
+		" ∧ ∀x (x ∨ v10)",
+	}
+
+	for _, suffix := range tests {
+		node := parse(t, strings.Join(names("v", 20), " ∧ ")+suffix)
+		s := NewBooleanSolver(NewEvaluationContext())
+		checks := 0
+		s.SetCancel(func() bool {
+			checks++
+			return checks > 10
+		})
+
+		if _, err := s.SolveRows(node, 0, 10, TrueRows); !errors.Is(err, ErrCancelled) {
+			t.Errorf("SolveRows(%s) = %v, want %v", node, err, ErrCancelled)
+		}
+		if checks != 11 {
+			t.Errorf("SolveRows(%s) checked for cancellation %d times, want 11", node, checks)
+		}
+	}
+}
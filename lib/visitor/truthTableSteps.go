@@ -0,0 +1,116 @@
+package visitor
+
+import (
+	"logicka/lib/ast"
+)
+
+// SubformulaValue is the value of a subformula on a row of a step-by-step
+// truth table.
+type SubformulaValue struct {
+	Formula string
+	Value   bool
+}
+
+// SolveSteps returns the truth table of node with a column for every
+// distinct subformula besides variables and truth values, as textbooks draw
+// it. Subformulas equal in the sense of ast.Equal share a column, named
+// after the first of them. The columns are ordered bottom-up, every
+// subformula after its own, so the last one is node itself. The bodies of
+// quantifiers and the arguments of predicates are not split up: they have
+// no value of their own on a row.
+//
+// The columns are computed in a single bottom-up pass, each from the columns
+// of its operands.
+func (s *BooleanSolver) SolveSteps(node ast.ASTNode) ([]TruthTableEntry, error) {
+	if err := s.layout(node, MaxSolveVariables); err != nil {
+		return nil, err
+	}
+	s.columns = make(map[string]bitVector, len(s.variables))
+
+	st := &steps{solver: s, seen: make(map[uint64][]int)}
+	result, err := st.evaluate(node)
+	if err != nil {
+		return nil, err
+	}
+
+	table := make([]TruthTableEntry, s.rows)
+	for row := range s.rows {
+		table[row] = s.explainedEntry(row, result)
+		values := make([]SubformulaValue, len(st.subformulas))
+		for i, name := range st.names {
+			values[i] = SubformulaValue{Formula: name, Value: st.columns[i].values.get(row)}
+		}
+		table[row].Subformulas = values
+	}
+	return table, nil
+}
+
+// steps collects the columns of the distinct subformulas of a formula.
+type steps struct {
+	solver      *BooleanSolver
+	subformulas []ast.ASTNode
+	names       []string
+	columns     []column
+	seen        map[uint64][]int // indices of the subformulas by hash
+}
+
+// evaluate returns the column of node, recording the columns of node and of
+// its subformulas that were not seen before.
+func (st *steps) evaluate(node ast.ASTNode) (column, error) {
+	s := st.solver
+	switch n := node.(type) {
+	case *ast.GroupingNode:
+		return st.evaluate(n.Expr)
+	case *ast.VariableNode, *ast.LiteralNode:
+		return Accept[column](node, s)
+	}
+
+	hash := node.Hash()
+	for _, i := range st.seen[hash] {
+		if ast.Equal(node, st.subformulas[i]) {
+			return st.columns[i], nil
+		}
+	}
+
+	var c column
+	var err error
+	switch n := node.(type) {
+	case *ast.BinaryNode:
+		var left, right column
+		if left, err = st.evaluate(n.Left); err != nil {
+			return column{}, err
+		}
+		if right, err = st.evaluate(n.Right); err != nil {
+			return column{}, err
+		}
+		c, err = s.binary(n, left, right)
+	case *ast.ChainNode:
+		if len(n.Operands) < 2 {
+			return Accept[column](node, s)
+		}
+		operands := make([]column, len(n.Operands))
+		for i, operand := range n.Operands {
+			if operands[i], err = st.evaluate(operand); err != nil {
+				return column{}, err
+			}
+		}
+		c, err = s.chain(n, operands)
+	case *ast.UnaryNode:
+		var operand column
+		if operand, err = st.evaluate(n.Operand); err != nil {
+			return column{}, err
+		}
+		c, err = s.unary(n, operand)
+	default:
+		c, err = Accept[column](node, s)
+	}
+	if err != nil {
+		return column{}, err
+	}
+
+	st.seen[hash] = append(st.seen[hash], len(st.subformulas))
+	st.subformulas = append(st.subformulas, node)
+	st.names = append(st.names, node.String())
+	st.columns = append(st.columns, c)
+	return c, nil
+}
@@ -0,0 +1,71 @@
+package visitor_test
+
+import (
+	"logicka/lib/parser"
+	"logicka/lib/visitor"
+	"testing"
+)
+
+func TestSolveSteps(t *testing.T) {
+	tests := []struct {
+		input   string
+		columns []string
+	}{
+		{"a", []string{}},
+		{"!(a)", []string{"!(a)"}},
+		{"(a ∧ b) → !(b ∧ a) ∨ c", []string{"a ∧ b", "!(b ∧ a)", "!(b ∧ a) ∨ c", "(a ∧ b) → !(b ∧ a) ∨ c"}},
+		{"(a ⊕ b) ~ (a ⊕ b)", []string{"a ⊕ b", "(a ⊕ b) ~ (a ⊕ b)"}},
+		{"∀x (x ∨ a) → a", []string{"∀x (x ∨ a)", "∀x (x ∨ a) → a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			table, err := visitor.NewBooleanSolver(visitor.NewEvaluationContext()).SolveSteps(node)
+			if err != nil {
+				t.Fatalf("SolveSteps(%q): %v", tt.input, err)
+			}
+			want, err := visitor.NewBooleanSolver(visitor.NewEvaluationContext()).Solve(node)
+			if err != nil {
+				t.Fatalf("Solve(%q): %v", tt.input, err)
+			}
+			if len(table) != len(want) {
+				t.Fatalf("SolveSteps(%q) has %d rows, want %d", tt.input, len(table), len(want))
+			}
+
+			for row, entry := range table {
+				if entry.Result != want[row].Result {
+					t.Errorf("row %d: result %v, want %v", row, entry.Result, want[row].Result)
+				}
+				if len(entry.Subformulas) != len(tt.columns) {
+					t.Fatalf("row %d: columns %v, want %v", row, entry.Subformulas, tt.columns)
+				}
+
+				// Every column holds the value of its subformula on the row.
+				values := make(map[string]bool, len(entry.Variables))
+				for _, variable := range entry.Variables {
+					values[variable.Name] = variable.Value
+				}
+				for i, column := range entry.Subformulas {
+					if column.Formula != tt.columns[i] {
+						t.Errorf("row %d: column %d is %s, want %s", row, i, column.Formula, tt.columns[i])
+					}
+					subformula, err := parser.Parse(column.Formula)
+					if err != nil {
+						t.Fatalf("Parse(%q): %v", column.Formula, err)
+					}
+					value, err := visitor.NewBooleanSolver(&visitor.EvaluationContext{Variables: values}).Solve(subformula)
+					if err != nil {
+						t.Fatalf("Solve(%q): %v", column.Formula, err)
+					}
+					if column.Value != value[0].Result {
+						t.Errorf("row %d: %s is %v, want %v", row, column.Formula, column.Value, value[0].Result)
+					}
+				}
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ package visitor
 import (
 	"fmt"
 	"logicka/lib/ast"
+	"logicka/lib/lexer"
 )
 
 // Custom error types for better error handling
@@ -17,10 +18,32 @@ func (e NodeTypeError) Error() string {
 
 type OperatorError struct {
 	Operator string
+	Span     lexer.Span // source span of the node using the operator
 }
 
 func (e OperatorError) Error() string {
-	return fmt.Sprintf("unknown operator: %s", e.Operator)
+	return fmt.Sprintf("unknown operator %s at position %d", e.Operator, e.Span.Start)
+}
+
+// TermError is returned when a term shows up where a formula is expected.
+type TermError struct {
+	Term string
+	Span lexer.Span
+}
+
+func (e TermError) Error() string {
+	return fmt.Sprintf("term %s at position %d is not a formula", e.Term, e.Span.Start)
+}
+
+// UnboundVariableError is returned when a term variable is neither bound by
+// a quantifier nor interpreted by the structure.
+type UnboundVariableError struct {
+	Name string
+	Span lexer.Span
+}
+
+func (e UnboundVariableError) Error() string {
+	return fmt.Sprintf("variable %s at position %d is not bound and is not a constant or domain element", e.Name, e.Span.Start)
 }
 
 // Visitor defines the interface for AST node visitors.
@@ -33,6 +56,7 @@ type Visitor[T any] interface {
 	VisitUnary(node *ast.UnaryNode) (T, error)
 	VisitPredicate(node *ast.PredicateNode) (T, error)
 	VisitQuantifier(node *ast.QuantifierNode) (T, error)
+	VisitFunction(node *ast.FunctionNode) (T, error)
 }
 
 // Accept dispatches the appropriate visitor method based on the node type.
@@ -54,6 +78,8 @@ func Accept[T any](node ast.ASTNode, visitor Visitor[T]) (T, error) {
 		return visitor.VisitPredicate(n)
 	case *ast.QuantifierNode:
 		return visitor.VisitQuantifier(n)
+	case *ast.FunctionNode:
+		return visitor.VisitFunction(n)
 	default:
 		var zero T
 		return zero, NodeTypeError{NodeType: fmt.Sprintf("%T", n)}
@@ -61,8 +87,14 @@ func Accept[T any](node ast.ASTNode, visitor Visitor[T]) (T, error) {
 }
 
 // EvaluationContext holds variable assignments for expression evaluation.
+// Structure interprets predicates, functions and quantifiers; formulas
+// without them need none. Order puts the variables of a truth table in a
+// chosen order: those it names come first, in that order, and the rest
+// follow alphabetically.
 type EvaluationContext struct {
 	Variables map[string]bool
+	Structure *Structure
+	Order     []string
 }
 
 func NewEvaluationContext() *EvaluationContext {
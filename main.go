@@ -13,6 +13,7 @@ import (
 var assets embed.FS
 
 func main() {
+	logicka := &lib.Logicka{}
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -23,9 +24,11 @@ func main() {
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
+		OnStartup:        logicka.Startup,
 		Bind: []interface{}{
-			&lib.Logicka{},
+			logicka,
 		},
+		ErrorFormatter: lib.FormatError,
 	})
 
 	if err != nil {